@@ -37,6 +37,8 @@ type AssetStatus struct {
 	Replicas int32 `json:"replicas,omitempty"`
 	// Selector is the label selector for pods corresponding to this asset deployment
 	Selector string `json:"selector,omitempty"`
+	// Certificates are the cert-manager certificates of the ingresses
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 }
 
 //+kubebuilder:object:root=true
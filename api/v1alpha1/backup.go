@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupPVCLabel is set on the VolumeSnapshots taken by the operator to the PVC they were taken of
+const BackupPVCLabel = "teranode.bsvblockchain.org/backup-pvc"
+
+// BackupDef takes CSI VolumeSnapshots of the shared and per-service PVCs on a schedule
+type BackupDef struct {
+	// Schedule is a cron expression, such as "0 3 * * *", evaluated in UTC
+	Schedule string `json:"schedule"`
+	// VolumeSnapshotClassName is the class of the snapshots, the default class of the cluster when not set
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// Retention is the number of snapshots kept for every PVC
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	Retention int32 `json:"retention,omitempty"`
+	// PauseBlockAssembly scales block assembly down while the snapshots are taken, so that no block is being assembled
+	PauseBlockAssembly bool `json:"pauseBlockAssembly,omitempty"`
+}
+
+// BackupStatus is the inventory of the snapshots taken by the operator
+type BackupStatus struct {
+	// LastScheduleTime is when the last snapshots were taken
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is when the last snapshots that are ready to use were taken
+	LastSuccessfulTime *metav1.Time     `json:"lastSuccessfulTime,omitempty"`
+	Snapshots          []SnapshotStatus `json:"snapshots,omitempty"`
+}
+
+// SnapshotStatus is the state of a VolumeSnapshot
+type SnapshotStatus struct {
+	Name                  string       `json:"name"`
+	PersistentVolumeClaim string       `json:"persistentVolumeClaim"`
+	CreationTime          *metav1.Time `json:"creationTime,omitempty"`
+	ReadyToUse            bool         `json:"readyToUse"`
+	RestoreSize           string       `json:"restoreSize,omitempty"`
+	Error                 string       `json:"error,omitempty"`
+}
@@ -26,6 +26,10 @@ type BlockAssemblySpec struct {
 	DeploymentOverrides *DeploymentOverrides         `json:"deploymentOverrides,omitempty"`
 	StorageClass        string                       `json:"storageClass,omitempty"`
 	StorageResources    *corev1.ResourceRequirements `json:"storageResources,omitempty"`
+	// StorageDataSource provisions the dedicated storage from a VolumeSnapshot or an existing PVC
+	StorageDataSource *corev1.TypedLocalObjectReference `json:"storageDataSource,omitempty"`
+	// WorkloadKind runs the service as a Deployment or a StatefulSet, defaults to Deployment
+	WorkloadKind WorkloadKind `json:"workloadKind,omitempty"`
 }
 
 // BlockAssemblyStatus defines the observed state of BlockAssembly
@@ -17,12 +17,19 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // BlockchainSpec defines the desired state of Blockchain
 type BlockchainSpec struct {
-	DeploymentOverrides *DeploymentOverrides `json:"deploymentOverrides,omitempty"`
+	DeploymentOverrides *DeploymentOverrides         `json:"deploymentOverrides,omitempty"`
+	StorageClass        string                       `json:"storageClass,omitempty"`
+	StorageResources    *corev1.ResourceRequirements `json:"storageResources,omitempty"`
+	// StorageDataSource provisions the dedicated storage from a VolumeSnapshot or an existing PVC
+	StorageDataSource *corev1.TypedLocalObjectReference `json:"storageDataSource,omitempty"`
+	// WorkloadKind runs the service as a Deployment or a StatefulSet, defaults to Deployment
+	WorkloadKind WorkloadKind `json:"workloadKind,omitempty"`
 }
 
 // BlockchainStatus defines the observed state of Blockchain
@@ -36,9 +43,21 @@ type BlockchainStatus struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:rbac:groups="",resources=endpoints;configmaps;services;secrets;persistentvolumeclaims,verbs=get;create;update;list;watch
+//+kubebuilder:rbac:groups="",resources=services;secrets;persistentvolumeclaims,verbs=delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups="",resources=pods;nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=create;delete
+//+kubebuilder:rbac:groups="",resources=nodes/proxy,verbs=get
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=patch
 //+kubebuilder:rbac:groups="discovery.k8s.io",resources=endpointslices,verbs=get;create;update;list;watch
-//+kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;update;create;list;watch
+//+kubebuilder:rbac:groups="apps",resources=deployments;statefulsets,verbs=get;update;create;list;watch;delete
 //+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses;networkpolicies,verbs=get;update;create;list;watch
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses;networkpolicies,verbs=delete
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes;grpcroutes;tlsroutes,verbs=get;update;create;delete;list;watch
+//+kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;update;create;delete;list;watch
+//+kubebuilder:rbac:groups="snapshot.storage.k8s.io",resources=volumesnapshots,verbs=get;create;list;watch;delete
+//+kubebuilder:rbac:groups="snapshot.storage.k8s.io",resources=volumesnapshotcontents,verbs=get;create;list;watch;delete
+//+kubebuilder:rbac:groups="traefik.io",resources=middlewares,verbs=get;update;create;list;watch;delete
 
 // Blockchain is the Schema for the blockchains API
 type Blockchain struct {
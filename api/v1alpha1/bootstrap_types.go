@@ -35,6 +35,8 @@ type BootstrapSpec struct {
 	ServiceAccount  string                       `json:"serviceAccount,omitempty"`
 	ConfigMapName   string                       `json:"configMapName,omitempty"`
 	Replicas        *int32                       `json:"replicas,omitempty"`
+	IPFamilies      []corev1.IPFamily            `json:"ipFamilies,omitempty"`
+	IPFamilyPolicy  *corev1.IPFamilyPolicy       `json:"ipFamilyPolicy,omitempty"`
 }
 
 // BootstrapStatus defines the observed state of Bootstrap
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -143,15 +144,88 @@ type ClusterSpec struct {
 	Image            string                         `json:"image,omitempty"`
 	Enabled          *bool                          `json:"enabled,omitempty"`
 	ImagePullSecrets *[]corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
-
-	SharedStorage       StorageConfig    `json:"sharedStorage"`
+	NodePool         string                         `json:"nodePool,omitempty"`
+
+	InitContainers    []corev1.Container `json:"initContainers,omitempty"`
+	SidecarContainers []SidecarContainer `json:"sidecarContainers,omitempty"`
+
+	PodLabels      map[string]string `json:"podLabels,omitempty"`
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	ServiceLabels      map[string]string      `json:"serviceLabels,omitempty"`
+	ServiceAnnotations map[string]string      `json:"serviceAnnotations,omitempty"`
+	IPFamilies         []corev1.IPFamily      `json:"ipFamilies,omitempty"`
+	IPFamilyPolicy     *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	SharedStorage StorageConfig `json:"sharedStorage"`
+	// Volumes are PVCs placing some of the stores apart from the shared storage
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=4
+	// +kubebuilder:validation:XValidation:rule="self.all(v, v.stores.all(s, self.filter(w, s in w.stores).size() == 1))",message="a store can only be on one volume"
+	Volumes             []VolumeDef      `json:"volumes,omitempty"`
 	AdditionalIngresses []v1.IngressSpec `json:"additionalIngresses,omitempty"`
+	// Gateway routes every ingress of the cluster through Gateway API routes attached to this Gateway
+	Gateway *GatewayRef `json:"gateway,omitempty"`
+	// Domain derives the hosts of the ingresses and exposed services that don't set one
+	Domain *DomainDef `json:"domain,omitempty"`
+	// NetworkPolicy restricts the traffic of every service to the dependency graph of the services
+	NetworkPolicy *NetworkPolicyDef `json:"networkPolicy,omitempty"`
+	// InternalTLS secures the gRPC traffic between the services with mutual TLS
+	InternalTLS *InternalTLSDef `json:"internalTLS,omitempty"`
+	// Backup takes VolumeSnapshots of the storage of the cluster on a schedule
+	Backup *BackupDef `json:"backup,omitempty"`
+}
+
+// InternalTLSDef issues a certificate to every service for mutual TLS between the services
+type InternalTLSDef struct {
+	// Issuer issues the certificates through cert-manager. The operator signs them with its own CA when not set.
+	Issuer *IssuerRef `json:"issuer,omitempty"`
+	// Duration of the certificates, defaults to 90 days
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// RenewBefore is how long before expiry the certificates are renewed, defaults to 30 days
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// NetworkPolicyDef generates a NetworkPolicy per service, allowing only the calls between services
+// the operator knows of, the ingress controllers and the internet traffic of the P2P services
+// +kubebuilder:validation:XValidation:rule="!self.enforce || (has(self.egress) && size(self.egress) > 0)",message="enforced network policies need egress rules to the stores"
+type NetworkPolicyDef struct {
+	// Enforce creates the NetworkPolicies. They are deleted when it is turned off.
+	Enforce bool `json:"enforce"`
+	// IngressControllerNamespaces may reach every service, defaults to ingress-nginx
+	IngressControllerNamespaces []string `json:"ingressControllerNamespaces,omitempty"`
+	// MonitoringNamespaces may reach every service, such as to scrape metrics
+	MonitoringNamespaces []string `json:"monitoringNamespaces,omitempty"`
+	// Egress is allowed from every service in addition to the dependency graph. The stores aren't part of the graph,
+	// so it's required when the policies are enforced.
+	Egress []v1.NetworkPolicyEgressRule `json:"egress,omitempty"`
 }
 
 type StorageConfig struct {
 	StorageResources *corev1.VolumeResourceRequirements `json:"storageResources,omitempty"`
 	StorageClass     string                             `json:"storageClass,omitempty"`
 	StorageVolume    string                             `json:"storageVolume,omitempty"`
+	// DataSource provisions the PVC from a VolumeSnapshot or an existing PVC. The services are only started
+	// once the restored PVC is bound.
+	DataSource *corev1.TypedLocalObjectReference `json:"dataSource,omitempty"`
+	// AutoExpand grows the PVC as its usage grows
+	AutoExpand *AutoExpandDef `json:"autoExpand,omitempty"`
+}
+
+// AutoExpandDef grows a PVC by a step when its usage crosses a threshold, up to a maximum size
+type AutoExpandDef struct {
+	// ThresholdPercent is the percentage of the volume used above which the PVC is grown
+	// +kubebuilder:default=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+	// Step is the size the PVC grows by
+	Step resource.Quantity `json:"step"`
+	// Maximum is the size the PVC doesn't grow beyond
+	Maximum resource.Quantity `json:"maximum"`
+	// PrometheusURL reads the usage from the kubelet_volume_stats metrics in Prometheus, instead of from the kubelet
+	PrometheusURL string `json:"prometheusURL,omitempty"`
 }
 
 // ClusterStatus defines the observed state of Cluster
@@ -159,6 +233,10 @@ type ClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions []metav1.Condition `json:"conditions"`
+	// Hostnames are the hosts the endpoints of the cluster are published on
+	Hostnames []EndpointHostname `json:"hostnames,omitempty"`
+	// Backup is the inventory of the snapshots of the cluster storage
+	Backup *BackupStatus `json:"backup,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,120 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Labels of the VolumeSnapshots a clone takes of its source, naming the clone
+const (
+	CloneLabel          = "teranode.bsvblockchain.org/clone"
+	CloneNamespaceLabel = "teranode.bsvblockchain.org/clone-namespace"
+)
+
+// CloneNamespacesAnnotation on a Cluster lists the namespaces, comma separated, it may be cloned into, or * for any.
+// A Cluster without it can't be cloned, since a clone reads its storage.
+const CloneNamespacesAnnotation = "teranode.bsvblockchain.org/clone-namespaces"
+
+// CloneFinalizer deletes the snapshots a clone took of its source, which are in other namespaces or
+// cluster-scoped and so aren't garbage collected with the clone
+const CloneFinalizer = "teranode.bsvblockchain.org/clone-snapshots"
+
+// ClonePhase is the progress of a clone
+type ClonePhase string
+
+const (
+	// ClonePhaseSnapshotting is while the storage of the source is being snapshotted
+	ClonePhaseSnapshotting ClonePhase = "Snapshotting"
+	// ClonePhaseRestoring is while the storage of the clone is being provisioned from the snapshots
+	ClonePhaseRestoring ClonePhase = "Restoring"
+	// ClonePhaseStarting is while the services of the clone aren't all ready
+	ClonePhaseStarting ClonePhase = "Starting"
+	// ClonePhaseServing is once every service of the clone is ready
+	ClonePhaseServing ClonePhase = "Serving"
+	// ClonePhaseFailed is when the clone can't proceed, such as without its source
+	ClonePhaseFailed ClonePhase = "Failed"
+)
+
+// ClusterCloneSpec defines the Cluster to clone, and how the clone differs from it
+type ClusterCloneSpec struct {
+	// Source is the Cluster to clone, in another namespace. The source has to allow the namespace of the clone
+	// in its teranode.bsvblockchain.org/clone-namespaces annotation.
+	Source ClusterCloneSource `json:"source"`
+	// ClusterName is the name of the clone, defaults to the name of the source
+	ClusterName string `json:"clusterName,omitempty"`
+	// Patch is a JSON merge patch applied to the spec of the source, such as a new image. The endpoints of the
+	// source aren't cloned: its storage volume, domain, ingresses, exposures and service annotations are cleared
+	// before the patch is applied, so the patch sets the ones of the clone.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Patch *runtime.RawExtension `json:"patch,omitempty"`
+	// VolumeSnapshotClassName is the class of the snapshots of the source storage, the default class when not set
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// ClusterCloneSource is the Cluster a clone is made of
+type ClusterCloneSource struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ClusterCloneStatus defines the progress of the clone
+type ClusterCloneStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Phase      ClonePhase         `json:"phase,omitempty"`
+	Message    string             `json:"message,omitempty"`
+	// Volumes are the PVCs of the source, and the snapshots they are cloned through
+	Volumes []ClonedVolume `json:"volumes,omitempty"`
+}
+
+// ClonedVolume is a PVC of the source cloned through a snapshot
+type ClonedVolume struct {
+	PersistentVolumeClaim string `json:"persistentVolumeClaim"`
+	Snapshot              string `json:"snapshot"`
+	ReadyToUse            bool   `json:"readyToUse"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.source.namespace`,description="Namespace of the source cluster"
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="Progress of the clone"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterClone is the Schema for the clusterclones API
+type ClusterClone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterCloneSpec   `json:"spec,omitempty"`
+	Status ClusterCloneStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterCloneList contains a list of ClusterClone
+type ClusterCloneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterClone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterClone{}, &ClusterCloneList{})
+}
@@ -36,6 +36,8 @@ type CoinbaseStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Certificates are the cert-manager certificates of the ingresses
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 }
 
 //+kubebuilder:object:root=true
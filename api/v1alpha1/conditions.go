@@ -11,3 +11,21 @@ const ReconciledReasonError = "Error"
 
 // ReconcileCompleteMessage is when the reconile is complete
 const ReconcileCompleteMessage = "Reconcile complete"
+
+// ConditionStorageRestored is whether the storage provisioned from a data source is bound
+const ConditionStorageRestored = "StorageRestored"
+
+// StorageRestoredReasonRestoring is while the storage is being provisioned from its data source
+const StorageRestoredReasonRestoring = "Restoring"
+
+// StorageRestoredReasonBound is once the storage provisioned from its data source is bound
+const StorageRestoredReasonBound = "Bound"
+
+// ConditionStorageMaximumReached is whether a PVC that grows automatically reached its maximum size
+const ConditionStorageMaximumReached = "StorageMaximumReached"
+
+// StorageMaximumReasonReached is when the PVC is at its maximum size
+const StorageMaximumReasonReached = "MaximumReached"
+
+// StorageMaximumReasonBelow is when the PVC can still grow
+const StorageMaximumReasonBelow = "BelowMaximum"
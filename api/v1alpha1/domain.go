@@ -0,0 +1,32 @@
+package v1alpha1
+
+// DefaultHostTemplate is the host template used when the domain doesn't set one
+const DefaultHostTemplate = "{{service}}.{{cluster}}.{{domain}}"
+
+// DomainDef derives the hosts of the cluster endpoints from a DNS domain
+type DomainDef struct {
+	// Name is the DNS domain, such as example.com
+	Name string `json:"name"`
+	// HostTemplate builds the host of an endpoint from {{service}}, {{cluster}}, {{namespace}} and {{domain}}.
+	// {{service}} is the name of the Ingress, or the app of an exposed service.
+	// +kubebuilder:default="{{service}}.{{cluster}}.{{domain}}"
+	HostTemplate string `json:"hostTemplate,omitempty"`
+	// ExternalDNS annotates the generated Ingresses and LoadBalancer Services for external-dns
+	ExternalDNS *ExternalDNSDef `json:"externalDNS,omitempty"`
+}
+
+// ExternalDNSDef configures the external-dns annotations of the cluster endpoints
+type ExternalDNSDef struct {
+	// TTL of the DNS records in seconds
+	TTL *int32 `json:"ttl,omitempty"`
+	// Annotations are added along with the hostname, such as provider specific settings
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// EndpointHostname is a host an endpoint of the cluster is published on
+type EndpointHostname struct {
+	// Name of the Ingress, Gateway API route or Service
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Host string `json:"host"`
+}
@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExposureType defines how P2P ports are reachable from outside the cluster
+// +kubebuilder:validation:Enum=LoadBalancer;NodePort;HostPort;HostNetwork
+type ExposureType string
+
+const (
+	// ExposureTypeLoadBalancer creates a LoadBalancer service for the P2P ports
+	ExposureTypeLoadBalancer ExposureType = "LoadBalancer"
+	// ExposureTypeNodePort creates a NodePort service for the P2P ports
+	ExposureTypeNodePort ExposureType = "NodePort"
+	// ExposureTypeHostPort binds the P2P ports on the node the pod runs on
+	ExposureTypeHostPort ExposureType = "HostPort"
+	// ExposureTypeHostNetwork runs the pod in the host network namespace
+	ExposureTypeHostNetwork ExposureType = "HostNetwork"
+)
+
+// ExposureDef defines how a service's P2P ports are exposed outside the cluster
+type ExposureDef struct {
+	// +kubebuilder:default=LoadBalancer
+	Type                     ExposureType                        `json:"type,omitempty"`
+	ExternalTrafficPolicy    corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	LoadBalancerSourceRanges []string                            `json:"loadBalancerSourceRanges,omitempty"`
+	LoadBalancerClass        *string                             `json:"loadBalancerClass,omitempty"`
+	// StaticIP is the address to request from the load balancer. It is set as the value of
+	// StaticIPAnnotation when given, since most cloud providers reserve addresses through an annotation.
+	StaticIP           string            `json:"staticIP,omitempty"`
+	StaticIPAnnotation string            `json:"staticIPAnnotation,omitempty"`
+	Annotations        map[string]string `json:"annotations,omitempty"`
+	// NodePorts pins the node port of a P2P port, keyed by port name
+	NodePorts map[string]int32 `json:"nodePorts,omitempty"`
+	// AdvertiseAddresses are the addresses the service advertises to its peers. When empty,
+	// the addresses of the LoadBalancer are advertised.
+	AdvertiseAddresses []string `json:"advertiseAddresses,omitempty"`
+}
@@ -37,6 +37,8 @@ type FaucetSpec struct {
 	ImagePullPolicy corev1.PullPolicy            `json:"imagePullPolicy,omitempty"`
 	ServiceAccount  string                       `json:"serviceAccount,omitempty"`
 	ConfigMapName   string                       `json:"configMapName,omitempty"`
+	IPFamilies      []corev1.IPFamily            `json:"ipFamilies,omitempty"`
+	IPFamilyPolicy  *corev1.IPFamilyPolicy       `json:"ipFamilyPolicy,omitempty"`
 }
 
 // FaucetStatus defines the observed state of Faucet
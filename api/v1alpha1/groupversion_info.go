@@ -34,6 +34,12 @@ var (
 	// TeranodeLabel is the label applied to all created teranode resources
 	TeranodeLabel = "teranode.bsvblockchain.org/part-of"
 
+	// NodePoolLabel is the node label and taint key used to dedicate nodes to a node pool
+	NodePoolLabel = "teranode.bsvblockchain.org/node-pool"
+
+	// ClusterLabel is the name of the cluster an endpoint with a published hostname belongs to
+	ClusterLabel = "teranode.bsvblockchain.org/cluster"
+
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
 )
@@ -1,8 +1,86 @@
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // IngressDef defines ingress spec and annotations
 type IngressDef struct {
 	ClassName   *string           `json:"className,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
-	Host        string            `json:"host"`
+	// Host is derived from the domain of the cluster when empty
+	Host string `json:"host,omitempty"`
+	// Gateway routes the ingress through a Gateway API route attached to this Gateway instead of an Ingress.
+	// The gateway of the cluster is used when not set.
+	Gateway *GatewayRef `json:"gateway,omitempty"`
+	// RouteType is the kind of Gateway API route to create. Defaults to GRPC for gRPC ingresses and HTTP otherwise.
+	RouteType RouteType `json:"routeType,omitempty"`
+	// TLS terminates TLS for the host on the ingress
+	TLS *IngressTLS `json:"tls,omitempty"`
+	// AllowedSourceRanges restricts the ingress to clients from these CIDRs
+	AllowedSourceRanges []string `json:"allowedSourceRanges,omitempty"`
+	// BasicAuthSecretRef requires HTTP basic authentication against the htpasswd users of this secret,
+	// stored under the auth key for nginx and the users key for traefik
+	BasicAuthSecretRef *corev1.LocalObjectReference `json:"basicAuthSecretRef,omitempty"`
+}
+
+// IngressTLS defines the certificate of an ingress
+type IngressTLS struct {
+	// SecretName is the secret holding the certificate, defaults to the ingress name suffixed with -tls
+	SecretName string `json:"secretName,omitempty"`
+	// Issuer creates a cert-manager Certificate for the host, stored in the secret
+	Issuer *IssuerRef `json:"issuer,omitempty"`
+}
+
+// IssuerRef references a cert-manager issuer
+type IssuerRef struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	Kind string `json:"kind,omitempty"`
+}
+
+// CertificateStatus is the observed state of a cert-manager Certificate created for an ingress
+type CertificateStatus struct {
+	Name     string       `json:"name"`
+	Ready    bool         `json:"ready"`
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	Message  string       `json:"message,omitempty"`
 }
+
+// ServiceIngress exposes a port of a service through an Ingress, or a Gateway API route
+type ServiceIngress struct {
+	// Name of the Ingress, defaults to the service name suffixed with the port name
+	Name string `json:"name,omitempty"`
+	// Port is the name of the service port to route to
+	Port string `json:"port"`
+	// Path defaults to /
+	Path string `json:"path,omitempty"`
+	// PathType defaults to Prefix
+	PathType   *networkingv1.PathType `json:"pathType,omitempty"`
+	IngressDef `json:",inline"`
+}
+
+// GatewayRef references the parent Gateway of Gateway API routes
+type GatewayRef struct {
+	Name string `json:"name"`
+	// Namespace of the Gateway, defaults to the namespace of the route
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the listener of the Gateway to attach to
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// RouteType is the kind of Gateway API route created for an ingress
+// +kubebuilder:validation:Enum=HTTP;GRPC;TLS
+type RouteType string
+
+const (
+	// RouteTypeHTTP creates an HTTPRoute
+	RouteTypeHTTP RouteType = "HTTP"
+	// RouteTypeGRPC creates a GRPCRoute
+	RouteTypeGRPC RouteType = "GRPC"
+	// RouteTypeTLS creates a TLSRoute, passing TLS through to the service
+	RouteTypeTLS RouteType = "TLS"
+)
@@ -29,6 +29,10 @@ type DeploymentOverrides struct {
 	ServiceAccount     string                         `json:"serviceAccount,omitempty"`
 	ConfigMapName      string                         `json:"configMapName,omitempty"`
 	ServiceAnnotations map[string]string              `json:"serviceAnnotations,omitempty"`
+	ServiceLabels      map[string]string              `json:"serviceLabels,omitempty"`
+	IPFamilies         []corev1.IPFamily              `json:"ipFamilies,omitempty"`
+	IPFamilyPolicy     *corev1.IPFamilyPolicy         `json:"ipFamilyPolicy,omitempty"`
+	Ingresses          []ServiceIngress               `json:"ingresses,omitempty"`
 	Replicas           *int32                         `json:"replicas,omitempty"`
 	Command            []string                       `json:"command,omitempty"`
 	Args               []string                       `json:"args,omitempty"`
@@ -37,4 +41,35 @@ type DeploymentOverrides struct {
 	EnvFrom            []corev1.EnvFromSource         `json:"envFrom,omitempty"`
 	Volumes            []corev1.Volume                `json:"volumes,omitempty"`
 	VolumeMounts       []corev1.VolumeMount           `json:"volumeMounts,omitempty"`
+	NodePool           string                         `json:"nodePool,omitempty"`
+	InitContainers     []corev1.Container             `json:"initContainers,omitempty"`
+	SidecarContainers  []SidecarContainer             `json:"sidecarContainers,omitempty"`
+	// Scratch attaches node-local scratch space, such as for caches
+	Scratch *ScratchDef `json:"scratch,omitempty"`
+
+	PodSecurityContext           *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	SecurityContext              *corev1.SecurityContext    `json:"securityContext,omitempty"`
+	AutomountServiceAccountToken *bool                      `json:"automountServiceAccountToken,omitempty"`
+
+	LivenessProbe  *ProbeOverride `json:"livenessProbe,omitempty"`
+	ReadinessProbe *ProbeOverride `json:"readinessProbe,omitempty"`
+	StartupProbe   *ProbeOverride `json:"startupProbe,omitempty"`
+
+	PodLabels      map[string]string `json:"podLabels,omitempty"`
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+}
+
+// ProbeOverride overrides a probe of the service container. A probe without a handler keeps the
+// default handler and only overrides the timings that are set.
+type ProbeOverride struct {
+	corev1.Probe `json:",inline"`
+	// Disabled removes the probe from the service container
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// SidecarContainer defines a native sidecar container that runs alongside the service container
+type SidecarContainer struct {
+	corev1.Container `json:",inline"`
+	// InheritEnv prepends the env and envFrom of the service container, including the cluster config references
+	InheritEnv bool `json:"inheritEnv,omitempty"`
 }
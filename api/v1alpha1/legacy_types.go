@@ -17,17 +17,27 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // LegacySpec defines the desired state of Legacy
 type LegacySpec struct {
-	DeploymentOverrides *DeploymentOverrides `json:"deploymentOverrides,omitempty"`
+	DeploymentOverrides *DeploymentOverrides         `json:"deploymentOverrides,omitempty"`
+	StorageClass        string                       `json:"storageClass,omitempty"`
+	StorageResources    *corev1.ResourceRequirements `json:"storageResources,omitempty"`
+	// StorageDataSource provisions the dedicated storage from a VolumeSnapshot or an existing PVC
+	StorageDataSource *corev1.TypedLocalObjectReference `json:"storageDataSource,omitempty"`
+	// WorkloadKind runs the service as a Deployment or a StatefulSet, defaults to Deployment
+	WorkloadKind WorkloadKind `json:"workloadKind,omitempty"`
+	Exposure     *ExposureDef `json:"exposure,omitempty"`
 }
 
 // LegacyStatus defines the observed state of Legacy
 type LegacyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ExternalAddresses are the host:port pairs the P2P ports are reachable on from outside the cluster
+	ExternalAddresses []string `json:"externalAddresses,omitempty"`
 }
 
 //+kubebuilder:object:root=true
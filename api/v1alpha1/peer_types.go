@@ -23,6 +23,7 @@ import (
 // PeerSpec defines the desired state of Peer
 type PeerSpec struct {
 	DeploymentOverrides *DeploymentOverrides `json:"deploymentOverrides,omitempty"`
+	Exposure            *ExposureDef         `json:"exposure,omitempty"`
 	GrpcIngress         *IngressDef          `json:"grpcIngress,omitempty"`
 	WsIngress           *IngressDef          `json:"wsIngress,omitempty"`
 	WssIngress          *IngressDef          `json:"wssIngress,omitempty"`
@@ -31,6 +32,10 @@ type PeerSpec struct {
 // PeerStatus defines the observed state of Peer
 type PeerStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ExternalAddresses are the host:port pairs the P2P ports are reachable on from outside the cluster
+	ExternalAddresses []string `json:"externalAddresses,omitempty"`
+	// Certificates are the cert-manager certificates of the ingresses
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -25,10 +25,14 @@ type PropagationSpec struct {
 	DeploymentOverrides *DeploymentOverrides `json:"deploymentOverrides,omitempty"`
 	ServiceAnnotations  map[string]string    `json:"serviceAnnotations,omitempty"`
 	DelveIngress        *IngressDef          `json:"delveIngress,omitempty"`
-	QuicIngress         *IngressDef          `json:"quicIngress,omitempty"`
-	GrpcIngress         *IngressDef          `json:"grpcIngress,omitempty"`
-	HTTPIngress         *IngressDef          `json:"httpIngress,omitempty"`
-	ProfilerIngress     *IngressDef          `json:"httpsIngress,omitempty"`
+	// QuicIngress is kept for compatibility. QUIC runs over UDP, which an Ingress can't route,
+	// so when QuicExposure isn't set it becomes a LoadBalancer exposure with the same annotations.
+	QuicIngress     *IngressDef `json:"quicIngress,omitempty"`
+	GrpcIngress     *IngressDef `json:"grpcIngress,omitempty"`
+	HTTPIngress     *IngressDef `json:"httpIngress,omitempty"`
+	ProfilerIngress *IngressDef `json:"httpsIngress,omitempty"`
+	// QuicExposure exposes the UDP QUIC port outside the cluster
+	QuicExposure *ExposureDef `json:"quicExposure,omitempty"`
 }
 
 // PropagationStatus defines the observed state of Propagation
@@ -38,6 +42,10 @@ type PropagationStatus struct {
 	Replicas int32 `json:"replicas,omitempty"`
 	// Selector is the label selector for pods corresponding to this propagation deployment
 	Selector string `json:"selector,omitempty"`
+	// ExternalAddresses are the host:port pairs the QUIC port is reachable on from outside the cluster
+	ExternalAddresses []string `json:"externalAddresses,omitempty"`
+	// Certificates are the cert-manager certificates of the ingresses
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -24,14 +24,38 @@ import (
 //nolint:godox // Kubebuilder-generated scaffolding comment
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// RotateCredentialsAnnotation rotates the generated RPC password whenever its value changes
+const RotateCredentialsAnnotation = "teranode.bsvblockchain.org/rotate-credentials"
+
 // RPCSpec defines the desired state of RPC
 type RPCSpec struct {
 	DeploymentOverrides *DeploymentOverrides `json:"deploymentOverrides,omitempty"`
+	// Ingress exposes the RPC port through an Ingress, or a Gateway API route
+	Ingress *IngressDef `json:"ingress,omitempty"`
+	// Exposure exposes the RPC port through a LoadBalancer or NodePort service, or on the node
+	Exposure *ExposureDef `json:"exposure,omitempty"`
+	// Credentials generates the RPC username and password and injects them into the RPC pods
+	Credentials *RPCCredentials `json:"credentials,omitempty"`
+}
+
+// RPCCredentials defines the credentials generated for the RPC service
+type RPCCredentials struct {
+	// Username defaults to teranode
+	Username string `json:"username,omitempty"`
+	// ConnectionSecretName is the secret publishing the RPC endpoint and credentials to consumers,
+	// defaults to rpc-connection
+	ConnectionSecretName string `json:"connectionSecretName,omitempty"`
 }
 
 // RPCStatus defines the observed state of RPC
 type RPCStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ExternalAddresses are the host:port pairs the RPC port is reachable on from outside the cluster
+	ExternalAddresses []string `json:"externalAddresses,omitempty"`
+	// Certificates are the cert-manager certificates of the ingress
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+	// CredentialsRotatedAt is when the RPC password was last generated
+	CredentialsRotatedAt *metav1.Time `json:"credentialsRotatedAt,omitempty"`
 }
 
 func (m *RPC) DeploymentOverrides() *DeploymentOverrides {
@@ -0,0 +1,31 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ScratchDef attaches node-local scratch space to the pods of a service. It's an emptyDir unless Ephemeral or
+// Local is set, Local taking precedence.
+type ScratchDef struct {
+	// MountPath of the scratch space, defaults to /scratch
+	MountPath string `json:"mountPath,omitempty"`
+	// EmptyDir is a directory of the node, or memory with the Memory medium, deleted with the pod
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+	// Ephemeral provisions a volume for every pod, deleted with the pod
+	Ephemeral *ScratchVolume `json:"ephemeral,omitempty"`
+	// Local provisions a volume for every pod from a storage class of local PVs, and only schedules the pods
+	// on the nodes that have PVs of the class
+	Local *ScratchVolume `json:"local,omitempty"`
+	// Settings are the Teranode settings set to the mount path. Defaults to the cache settings of the service,
+	// or scratch_dir, when no setting is set.
+	Settings []string `json:"settings,omitempty"`
+	// URLSettings are the Teranode settings set to a file:// URL of the mount path
+	URLSettings []string `json:"urlSettings,omitempty"`
+}
+
+// ScratchVolume is a volume provisioned for every pod
+type ScratchVolume struct {
+	StorageClass string            `json:"storageClass,omitempty"`
+	Size         resource.Quantity `json:"size"`
+}
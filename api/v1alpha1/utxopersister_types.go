@@ -17,12 +17,19 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // UtxoPersisterSpec defines the desired state of UtxoPersister
 type UtxoPersisterSpec struct {
-	DeploymentOverrides *DeploymentOverrides `json:"deploymentOverrides,omitempty"`
+	DeploymentOverrides *DeploymentOverrides         `json:"deploymentOverrides,omitempty"`
+	StorageClass        string                       `json:"storageClass,omitempty"`
+	StorageResources    *corev1.ResourceRequirements `json:"storageResources,omitempty"`
+	// StorageDataSource provisions the dedicated storage from a VolumeSnapshot or an existing PVC
+	StorageDataSource *corev1.TypedLocalObjectReference `json:"storageDataSource,omitempty"`
+	// WorkloadKind runs the service as a Deployment or a StatefulSet, defaults to Deployment
+	WorkloadKind WorkloadKind `json:"workloadKind,omitempty"`
 }
 
 // UtxoPersisterStatus defines the observed state of UtxoPersister
@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Store is a Teranode blob store that can be placed on a volume of its own
+// +kubebuilder:validation:Enum=blocks;subtrees;txs;temp
+type Store string
+
+const (
+	// StoreBlocks holds the blocks, and the UTXO files written by block persister and UTXO persister
+	StoreBlocks Store = "blocks"
+	// StoreSubtrees holds the subtrees
+	StoreSubtrees Store = "subtrees"
+	// StoreTxs holds the transaction blobs
+	StoreTxs Store = "txs"
+	// StoreTemp holds the temporary files
+	StoreTemp Store = "temp"
+)
+
+// VolumeDef is a named PVC holding some of the stores of the cluster, instead of the shared storage
+type VolumeDef struct {
+	// Name of the volume, its PVC is named cluster-storage-<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name             string                             `json:"name"`
+	StorageClass     string                             `json:"storageClass,omitempty"`
+	StorageResources *corev1.VolumeResourceRequirements `json:"storageResources,omitempty"`
+	// AccessModes of the PVC, defaults to ReadWriteMany since every service using the stores mounts it
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// DataSource provisions the PVC from a VolumeSnapshot or an existing PVC
+	DataSource *corev1.TypedLocalObjectReference `json:"dataSource,omitempty"`
+	// Stores placed on the volume. A store can only be on one volume.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=4
+	// +listType=set
+	Stores []Store `json:"stores"`
+}
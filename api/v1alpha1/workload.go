@@ -0,0 +1,12 @@
+package v1alpha1
+
+// WorkloadKind defines the kind of workload a service runs as
+// +kubebuilder:validation:Enum=Deployment;StatefulSet
+type WorkloadKind string
+
+const (
+	// WorkloadKindDeployment runs the service as a Deployment, its replicas sharing its storage
+	WorkloadKindDeployment WorkloadKind = "Deployment"
+	// WorkloadKindStatefulSet runs the service as a StatefulSet, with storage and a network identity per replica
+	WorkloadKindStatefulSet WorkloadKind = "StatefulSet"
+)
@@ -34,6 +34,7 @@ import (
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -41,6 +42,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/bsv-blockchain/teranode-operator/internal/controller"
 )
@@ -104,6 +107,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := gatewayv1.Install(scheme); err != nil {
+		setupLog.Error(err, "unable to add gateway.networking.k8s.io/v1 scheme")
+		os.Exit(1)
+	}
+
+	if err := gatewayv1alpha2.Install(scheme); err != nil {
+		setupLog.Error(err, "unable to add gateway.networking.k8s.io/v1alpha2 scheme")
+		os.Exit(1)
+	}
+
 	//+kubebuilder:scaffold:scheme
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
@@ -170,13 +183,29 @@ func main() {
 		os.Exit(1)
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create kubernetes client")
+		os.Exit(1)
+	}
 	if err = (&controller.ClusterReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		VolumeStats: &controller.KubeletVolumeStats{
+			Client: mgr.GetClient(),
+			REST:   clientset.CoreV1().RESTClient(),
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, CreateControllerError, "controller", "Cluster")
 		os.Exit(1)
 	}
+	if err = (&controller.ClusterCloneReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, CreateControllerError, "controller", "ClusterClone")
+		os.Exit(1)
+	}
 	if err = (&controller.AssetReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -39,6 +39,7 @@ func (r *AlertSystemReconciler) updateService(svc *corev1.Service, alert *terano
 		return err
 	}
 	svc.Spec = *defaultAlertSystemServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, alert)
 	return nil
 }
 
@@ -76,3 +77,15 @@ func defaultAlertSystemServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *AlertSystemReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	alert := teranodev1alpha1.AlertSystem{}
+	if err := r.Get(r.Context, r.NamespacedName, &alert); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &alert, "alert", "alert"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
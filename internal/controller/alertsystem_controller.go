@@ -72,6 +72,7 @@ func (r *AlertSystemReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	_, err := utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
 	)
 
 	if err != nil {
@@ -78,6 +78,8 @@ func (r *AssetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	_, err := utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
+		r.ReconcileGrpcRoute,
 		r.ReconcileHTTPIngress,
 		r.ReconcileHTTPSIngress,
 	)
@@ -118,6 +120,12 @@ func (r *AssetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		)
 	}
 
+	certificates, certErr := certificateStatuses(ctx, r.Client, &asset)
+	if certErr != nil {
+		r.Log.Error(certErr, "unable to fetch certificates")
+	}
+	asset.Status.Certificates = certificates
+
 	err = r.Client.Status().Update(ctx, &asset)
 
 	if asset.Spec.DeploymentOverrides != nil && asset.Spec.DeploymentOverrides.Replicas != nil {
@@ -127,6 +135,10 @@ func (r *AssetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 	}
 
+	if !certificatesReady(asset.Status.Certificates) {
+		return ctrl.Result{RequeueAfter: CertificateRequeueInterval}, err
+	}
+
 	return ctrl.Result{Requeue: false, RequeueAfter: 0}, err
 }
 
@@ -137,6 +149,6 @@ func (r *AssetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&networkingv1.Ingress{}).
 		Owns(&corev1.Service{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		Complete(r)
 }
@@ -6,7 +6,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -71,6 +70,7 @@ func defaultAssetDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -79,6 +79,7 @@ func defaultAssetDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "asset",
+						SecurityContext: defaultContainerSecurityContext(),
 						// Make sane defaults, and this should be configurable
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -89,40 +90,9 @@ func defaultAssetDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("1Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						StartupProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							FailureThreshold: 30,
-							PeriodSeconds:    10,
-						},
+						ReadinessProbe: defaultReadinessProbe(HealthPort),
+						LivenessProbe:  defaultLivenessProbe(HealthPort),
+						StartupProbe:   defaultStartupProbe(HealthPort, DefaultStartupWindow),
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: DebuggerPort,
@@ -150,6 +120,7 @@ func defaultAssetDeploymentSpec() *appsv1.DeploymentSpec {
 								MountPath: "/data",
 								Name:      SharedPVCName,
 							},
+							tmpVolumeMount(),
 						},
 					},
 				},
@@ -162,6 +133,7 @@ func defaultAssetDeploymentSpec() *appsv1.DeploymentSpec {
 							},
 						},
 					},
+					tmpVolume(),
 				},
 			},
 		},
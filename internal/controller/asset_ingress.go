@@ -2,162 +2,79 @@ package controller
 
 import (
 	"github.com/go-logr/logr"
-	v1 "k8s.io/api/networking/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
 )
 
-// ReconcileHTTPIngress is the ingress for the asset grpc server
+// ReconcileHTTPIngress is the ingress for the asset http server
 func (r *AssetReconciler) ReconcileHTTPIngress(log logr.Logger) (bool, error) {
 	asset := teranodev1alpha1.Asset{}
 	if err := r.Get(r.Context, r.NamespacedName, &asset); err != nil {
 		return false, err
 	}
-	// Skip if HTTPIngress isn't set
 	if asset.Spec.HTTPIngress == nil {
-		return false, nil
-	}
-	labels := getAppLabels("asset")
-	prefix := v1.PathTypePrefix
-	ingress := &v1.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        "asset-http",
-			Namespace:   r.NamespacedName.Namespace,
-			Annotations: asset.Spec.HTTPIngress.Annotations,
-			Labels:      labels,
-		},
-		Spec: v1.IngressSpec{
-			Rules: []v1.IngressRule{
-				{
-					Host: asset.Spec.GrpcIngress.Host,
-					IngressRuleValue: v1.IngressRuleValue{
-						HTTP: &v1.HTTPIngressRuleValue{
-							Paths: []v1.HTTPIngressPath{
-								{
-									Path:     "/",
-									PathType: &prefix,
-									Backend: v1.IngressBackend{
-										Service: &v1.IngressServiceBackend{
-											Name: "asset",
-											Port: v1.ServiceBackendPort{
-												Name: "asset-http",
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, ingress, func() error {
-		return r.updateHTTPIngress(ingress, &asset)
-	})
-	if err != nil {
+		return true, nil
+	}
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &asset, asset.Spec.HTTPIngress, routeTarget{
+		Name:        "asset-http",
+		App:         "asset",
+		ServiceName: "asset",
+		Port:        AssetHTTPPort,
+		RouteType:   teranodev1alpha1.RouteTypeHTTP,
+	}); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func (r *AssetReconciler) updateHTTPIngress(ingress *v1.Ingress, asset *teranodev1alpha1.Asset) error {
-	err := controllerutil.SetControllerReference(asset, ingress, r.Scheme)
-	if err != nil {
-		return err
-	}
-	if asset.Spec.HTTPIngress == nil {
-		return nil
-	}
-	if asset.Spec.HTTPIngress.Annotations == nil {
-		ingress.Annotations = asset.Spec.HTTPIngress.Annotations
-	}
-	if asset.Spec.HTTPIngress.ClassName != nil {
-		ingress.Spec.IngressClassName = asset.Spec.HTTPIngress.ClassName
-	}
-
-	return nil
-}
-
-// ReconcileHTTPSIngress is the ingress for the asset grpc server
+// ReconcileHTTPSIngress is the TLS terminated ingress for the asset http server
 func (r *AssetReconciler) ReconcileHTTPSIngress(log logr.Logger) (bool, error) {
 	asset := teranodev1alpha1.Asset{}
 	if err := r.Get(r.Context, r.NamespacedName, &asset); err != nil {
 		return false, err
 	}
-	// Skip if domain isn't set
 	if asset.Spec.HTTPSIngress == nil {
-		return false, nil
-	}
-
-	labels := getAppLabels("asset")
-	prefix := v1.PathTypePrefix
-	ingress := &v1.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        "asset-https",
-			Namespace:   r.NamespacedName.Namespace,
-			Annotations: asset.Spec.HTTPSIngress.Annotations,
-			Labels:      labels,
-		},
-		Spec: v1.IngressSpec{
-			Rules: []v1.IngressRule{
-				{
-					Host: asset.Spec.GrpcIngress.Host,
-					IngressRuleValue: v1.IngressRuleValue{
-						HTTP: &v1.HTTPIngressRuleValue{
-							Paths: []v1.HTTPIngressPath{
-								{
-									Path:     "/",
-									PathType: &prefix,
-									Backend: v1.IngressBackend{
-										Service: &v1.IngressServiceBackend{
-											Name: "asset",
-											Port: v1.ServiceBackendPort{
-												Name: "asset-http",
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-			TLS: []v1.IngressTLS{
-				{
-					Hosts:      []string{asset.Spec.HTTPSIngress.Host},
-					SecretName: "asset-tls",
-				},
-			},
-		},
-	}
-
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, ingress, func() error {
-		return r.updateHTTPSIngress(ingress, &asset)
-	})
-	if err != nil {
+		return true, nil
+	}
+	def := *asset.Spec.HTTPSIngress
+	if def.TLS == nil {
+		def.TLS = &teranodev1alpha1.IngressTLS{SecretName: "asset-tls"}
+	}
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &asset, &def, routeTarget{
+		Name:        "asset-https",
+		App:         "asset",
+		ServiceName: "asset",
+		Port:        AssetHTTPPort,
+		RouteType:   teranodev1alpha1.RouteTypeHTTP,
+	}); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func (r *AssetReconciler) updateHTTPSIngress(ingress *v1.Ingress, asset *teranodev1alpha1.Asset) error {
-	err := controllerutil.SetControllerReference(asset, ingress, r.Scheme)
-	if err != nil {
-		return err
+// ReconcileGrpcRoute is the Gateway API route for the asset grpc server. gRPC needs a GRPCRoute,
+// so it is only created when the grpc ingress goes through a gateway.
+func (r *AssetReconciler) ReconcileGrpcRoute(log logr.Logger) (bool, error) {
+	asset := teranodev1alpha1.Asset{}
+	if err := r.Get(r.Context, r.NamespacedName, &asset); err != nil {
+		return false, err
 	}
-	if asset.Spec.HTTPSIngress == nil {
-		return nil
+	if asset.Spec.GrpcIngress == nil {
+		return true, nil
 	}
-	if asset.Spec.HTTPSIngress.Annotations == nil {
-		ingress.Annotations = asset.Spec.HTTPSIngress.Annotations
+	target := routeTarget{
+		Name:        "asset-grpc",
+		App:         "asset",
+		ServiceName: "asset",
+		Port:        AssetGRPCPort,
+		RouteType:   teranodev1alpha1.RouteTypeGRPC,
 	}
-	if asset.Spec.HTTPSIngress.ClassName != nil {
-		ingress.Spec.IngressClassName = asset.Spec.HTTPSIngress.ClassName
+	if err := validateIngressAccess(r.Client, &asset, asset.Spec.GrpcIngress, target); err != nil {
+		return false, err
 	}
-
-	return nil
+	_, err := reconcileGatewayRoute(r.Context, r.Client, r.Scheme, &asset, asset.Spec.GrpcIngress, target)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
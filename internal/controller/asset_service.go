@@ -39,6 +39,7 @@ func (r *AssetReconciler) updateService(svc *corev1.Service, asset *teranodev1al
 		return err
 	}
 	svc.Spec = *defaultAssetServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, asset)
 	return nil
 }
 
@@ -70,3 +71,15 @@ func defaultAssetServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *AssetReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	asset := teranodev1alpha1.Asset{}
+	if err := r.Get(r.Context, r.NamespacedName, &asset); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &asset, "asset", "asset"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
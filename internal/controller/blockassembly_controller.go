@@ -70,6 +70,7 @@ func (r *BlockAssemblyReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	_, err := utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
 	)
 
 	if err != nil {
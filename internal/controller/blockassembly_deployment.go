@@ -6,7 +6,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -20,20 +19,9 @@ func (r *BlockAssemblyReconciler) ReconcileDeployment(log logr.Logger) (bool, er
 	if err := r.Get(r.Context, r.NamespacedName, &blockAssembly); err != nil {
 		return false, err
 	}
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "block-assembly",
-			Namespace: r.NamespacedName.Namespace,
-			Labels:    getAppLabels("block-assembly"),
-		},
-	}
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &dep, func() error {
-		return r.updateDeployment(&dep, &blockAssembly)
+	return reconcileWorkload(r.Context, r.Client, r.Scheme, &blockAssembly, blockAssemblyWorkload(&blockAssembly), func(dep *appsv1.Deployment) error {
+		return r.updateDeployment(dep, &blockAssembly)
 	})
-	if err != nil {
-		return false, err
-	}
-	return true, nil
 }
 
 func (r *BlockAssemblyReconciler) updateDeployment(dep *appsv1.Deployment, blockAssembly *teranodev1alpha1.BlockAssembly) error {
@@ -74,6 +62,7 @@ func defaultBlockAssemblyDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -82,6 +71,7 @@ func defaultBlockAssemblyDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "block-assembly",
+						SecurityContext: defaultContainerSecurityContext(),
 						// Make sane defaults, and this should be configurable
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -92,40 +82,9 @@ func defaultBlockAssemblyDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("4Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						StartupProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							FailureThreshold: 30,
-							PeriodSeconds:    10,
-						},
+						ReadinessProbe: defaultReadinessProbe(HealthPort),
+						LivenessProbe:  defaultLivenessProbe(HealthPort),
+						StartupProbe:   defaultStartupProbe(HealthPort, SyncStartupWindow),
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: BlockAssemblyPort,
@@ -149,6 +108,7 @@ func defaultBlockAssemblyDeploymentSpec() *appsv1.DeploymentSpec {
 								MountPath: "/data/",
 								Name:      SharedPVCName,
 							},
+							tmpVolumeMount(),
 						},
 					},
 				},
@@ -161,8 +121,21 @@ func defaultBlockAssemblyDeploymentSpec() *appsv1.DeploymentSpec {
 							},
 						},
 					},
+					tmpVolume(),
 				},
 			},
 		},
 	}
 }
+
+// blockAssemblyWorkload is the Deployment or StatefulSet of the block assembly service
+func blockAssemblyWorkload(blockAssembly *teranodev1alpha1.BlockAssembly) workload {
+	return workload{
+		App:              "block-assembly",
+		Kind:             blockAssembly.Spec.WorkloadKind,
+		DataPath:         BlockAssemblyDataPath,
+		StorageClass:     blockAssembly.Spec.StorageClass,
+		StorageResources: blockAssembly.Spec.StorageResources,
+		DataSource:       blockAssembly.Spec.StorageDataSource,
+	}
+}
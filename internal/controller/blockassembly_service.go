@@ -39,6 +39,7 @@ func (r *BlockAssemblyReconciler) updateService(svc *corev1.Service, blockassemb
 		return err
 	}
 	svc.Spec = *defaultBlockAssemblyServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, blockassembly)
 	return nil
 }
 
@@ -70,3 +71,15 @@ func defaultBlockAssemblyServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *BlockAssemblyReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	blockassembly := teranodev1alpha1.BlockAssembly{}
+	if err := r.Get(r.Context, r.NamespacedName, &blockassembly); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &blockassembly, "block-assembly", "block-assembly"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
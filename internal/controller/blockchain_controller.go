@@ -74,6 +74,7 @@ func (r *BlockchainReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// r.Validate,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
 	)
 
 	if err != nil {
@@ -119,7 +120,8 @@ func (r *BlockchainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&teranodev1alpha1.Blockchain{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		Complete(r)
 }
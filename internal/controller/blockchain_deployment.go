@@ -6,7 +6,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -20,21 +19,9 @@ func (r *BlockchainReconciler) ReconcileDeployment(log logr.Logger) (bool, error
 	if err := r.Get(r.Context, r.NamespacedName, &blockchain); err != nil {
 		return false, err
 	}
-	labels := getAppLabels("blockchain")
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "blockchain",
-			Namespace: r.NamespacedName.Namespace,
-			Labels:    labels,
-		},
-	}
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &dep, func() error {
-		return r.updateDeployment(&dep, &blockchain)
+	return reconcileWorkload(r.Context, r.Client, r.Scheme, &blockchain, blockchainWorkload(&blockchain), func(dep *appsv1.Deployment) error {
+		return r.updateDeployment(dep, &blockchain)
 	})
-	if err != nil {
-		return false, err
-	}
-	return true, nil
 }
 
 func (r *BlockchainReconciler) updateDeployment(dep *appsv1.Deployment, blockchain *teranodev1alpha1.Blockchain) error {
@@ -72,6 +59,7 @@ func defaultBlockchainDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Affinity: &corev1.Affinity{
 					PodAntiAffinity: &corev1.PodAntiAffinity{
 						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
@@ -97,6 +85,7 @@ func defaultBlockchainDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "blockchain",
+						SecurityContext: defaultContainerSecurityContext(),
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
 								corev1.ResourceMemory: resource.MustParse("2Gi"),
@@ -106,30 +95,9 @@ func defaultBlockchainDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("1Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
+						ReadinessProbe:           defaultReadinessProbe(HealthPort),
+						LivenessProbe:            defaultLivenessProbe(HealthPort),
+						StartupProbe:             defaultStartupProbe(HealthPort, BlockchainStartupWindow),
 						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						Ports: []corev1.ContainerPort{
 							{
@@ -153,11 +121,23 @@ func defaultBlockchainDeploymentSpec() *appsv1.DeploymentSpec {
 								Protocol:      corev1.ProtocolTCP,
 							},
 						},
-						VolumeMounts: []corev1.VolumeMount{},
+						VolumeMounts: []corev1.VolumeMount{tmpVolumeMount()},
 					},
 				},
-				Volumes: []corev1.Volume{},
+				Volumes: []corev1.Volume{tmpVolume()},
 			},
 		},
 	}
 }
+
+// blockchainWorkload is the Deployment or StatefulSet of the blockchain service
+func blockchainWorkload(blockchain *teranodev1alpha1.Blockchain) workload {
+	return workload{
+		App:              "blockchain",
+		Kind:             blockchain.Spec.WorkloadKind,
+		DataPath:         BlockchainDataPath,
+		StorageClass:     blockchain.Spec.StorageClass,
+		StorageResources: blockchain.Spec.StorageResources,
+		DataSource:       blockchain.Spec.StorageDataSource,
+	}
+}
@@ -39,6 +39,7 @@ func (r *BlockchainReconciler) updateService(svc *corev1.Service, blockchain *te
 		return err
 	}
 	svc.Spec = *defaultBlockchainServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, blockchain)
 	return nil
 }
 
@@ -76,3 +77,15 @@ func defaultBlockchainServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *BlockchainReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	blockchain := teranodev1alpha1.Blockchain{}
+	if err := r.Get(r.Context, r.NamespacedName, &blockchain); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &blockchain, BlockchainServiceName, BlockchainServiceName); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -6,7 +6,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -20,20 +19,9 @@ func (r *BlockPersisterReconciler) ReconcileDeployment(log logr.Logger) (bool, e
 	if err := r.Get(r.Context, r.NamespacedName, &blockPersister); err != nil {
 		return false, err
 	}
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "block-persister",
-			Namespace: r.NamespacedName.Namespace,
-			Labels:    getAppLabels("block-persister"),
-		},
-	}
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &dep, func() error {
-		return r.updateDeployment(&dep, &blockPersister)
+	return reconcileWorkload(r.Context, r.Client, r.Scheme, &blockPersister, blockPersisterWorkload(&blockPersister), func(dep *appsv1.Deployment) error {
+		return r.updateDeployment(dep, &blockPersister)
 	})
-	if err != nil {
-		return false, err
-	}
-	return true, nil
 }
 
 func (r *BlockPersisterReconciler) updateDeployment(dep *appsv1.Deployment, blockPersister *teranodev1alpha1.BlockPersister) error {
@@ -71,6 +59,7 @@ func defaultBlockPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -79,6 +68,7 @@ func defaultBlockPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "block-persister",
+						SecurityContext: defaultContainerSecurityContext(),
 						// Make sane defaults, and this should be configurable
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -89,40 +79,9 @@ func defaultBlockPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("1Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						StartupProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							FailureThreshold: 30,
-							PeriodSeconds:    10,
-						},
+						ReadinessProbe: defaultReadinessProbe(HealthPort),
+						LivenessProbe:  defaultLivenessProbe(HealthPort),
+						StartupProbe:   defaultStartupProbe(HealthPort, SyncStartupWindow),
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: DebuggerPort,
@@ -138,6 +97,7 @@ func defaultBlockPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 								MountPath: "/data",
 								Name:      SharedPVCName,
 							},
+							tmpVolumeMount(),
 						},
 					},
 				},
@@ -150,8 +110,21 @@ func defaultBlockPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 							},
 						},
 					},
+					tmpVolume(),
 				},
 			},
 		},
 	}
 }
+
+// blockPersisterWorkload is the Deployment or StatefulSet of the block persister service
+func blockPersisterWorkload(blockPersister *teranodev1alpha1.BlockPersister) workload {
+	return workload{
+		App:              "block-persister",
+		Kind:             blockPersister.Spec.WorkloadKind,
+		DataPath:         BlockPersisterDataPath,
+		StorageClass:     blockPersister.Spec.StorageClass,
+		StorageResources: blockPersister.Spec.StorageResources,
+		DataSource:       blockPersister.Spec.StorageDataSource,
+	}
+}
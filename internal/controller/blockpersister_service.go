@@ -39,6 +39,7 @@ func (r *BlockPersisterReconciler) updateService(svc *corev1.Service, blockPersi
 		return err
 	}
 	svc.Spec = *defaultBlockPersisterServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, blockPersister)
 	return nil
 }
 
@@ -64,3 +65,15 @@ func defaultBlockPersisterServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *BlockPersisterReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	blockPersister := teranodev1alpha1.BlockPersister{}
+	if err := r.Get(r.Context, r.NamespacedName, &blockPersister); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &blockPersister, "block-persister", "block-persister"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
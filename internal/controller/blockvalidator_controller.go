@@ -72,6 +72,7 @@ func (r *BlockValidatorReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		// r.Validate,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
 	)
 
 	if err != nil {
@@ -39,6 +39,7 @@ func (r *BlockValidatorReconciler) updateService(svc *corev1.Service, blockValid
 		return err
 	}
 	svc.Spec = *defaultBlockValidatorServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, blockValidator)
 	return nil
 }
 
@@ -76,3 +77,15 @@ func defaultBlockValidatorServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *BlockValidatorReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	blockValidator := teranodev1alpha1.BlockValidator{}
+	if err := r.Get(r.Context, r.NamespacedName, &blockValidator); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &blockValidator, "block-validator", "block-validation"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
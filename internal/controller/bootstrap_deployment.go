@@ -108,6 +108,7 @@ func defaultBootstrapDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Affinity: &corev1.Affinity{
 					PodAntiAffinity: &corev1.PodAntiAffinity{
 						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
@@ -133,6 +134,8 @@ func defaultBootstrapDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "bootstrap",
+						SecurityContext: defaultContainerSecurityContext(),
+						VolumeMounts:    []corev1.VolumeMount{tmpVolumeMount()},
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
 								corev1.ResourceMemory: resource.MustParse("4Gi"),
@@ -187,7 +190,7 @@ func defaultBootstrapDeploymentSpec() *appsv1.DeploymentSpec {
 						},
 					},
 				},
-				Volumes: []corev1.Volume{},
+				Volumes: []corev1.Volume{tmpVolume()},
 			},
 		},
 	}
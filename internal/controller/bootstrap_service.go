@@ -39,6 +39,7 @@ func (r *BootstrapReconciler) updateService(svc *corev1.Service, bs *teranodev1a
 		return err
 	}
 	svc.Spec = *defaultBootstrapServiceSpec()
+	utils.ApplyIPFamilies(svc, bs.Spec.IPFamilies, bs.Spec.IPFamilyPolicy)
 	return nil
 }
 
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// DefaultAutoExpandThresholdPercent is the usage above which a PVC grows when the policy doesn't set one
+const DefaultAutoExpandThresholdPercent = 80
+
+// ReconcileAutoExpand grows the shared PVC by a step when its usage crosses the threshold of the auto expand
+// policy, up to its maximum. It waits for a previous expansion to complete, so that a crossing grows it once.
+// Failing to read the usage doesn't fail the reconcile.
+func (r *ClusterReconciler) ReconcileAutoExpand(log logr.Logger) (bool, error) {
+	cluster := teranodev1alpha1.Cluster{}
+	if err := r.Get(r.Context, r.NamespacedName, &cluster); err != nil {
+		return false, err
+	}
+	def := cluster.Spec.SharedStorage.AutoExpand
+	if def == nil {
+		return true, nil
+	}
+	pvc := corev1.PersistentVolumeClaim{}
+	err := r.Get(r.Context, types.NamespacedName{Name: SharedPVCName, Namespace: cluster.Namespace}, &pvc)
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound || resizeInProgress(&pvc) {
+		return true, nil
+	}
+
+	stats := r.volumeStats(def)
+	if stats == nil {
+		log.Info("no volume stats reader, the shared PVC won't be expanded")
+		return true, nil
+	}
+	used, _, err := stats.VolumeUsage(r.Context, &pvc)
+	if err != nil {
+		if !errors.Is(err, ErrVolumeStatsNotFound) {
+			log.Error(err, "unable to read the usage of the shared PVC")
+		}
+		return true, nil
+	}
+	capacity := pvc.Status.Capacity.Storage()
+	size, grow := autoExpandSize(def, capacity, used)
+	if !grow {
+		return true, nil
+	}
+
+	log.Info("expanding the shared PVC", "used", used, "capacity", capacity.String(), "size", size.String())
+	patch := client.MergeFrom(pvc.DeepCopy())
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+	err = r.Patch(r.Context, &pvc, patch)
+	// the storage class may not allow expansion
+	if err != nil && !k8serrors.IsForbidden(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// volumeStats reads from Prometheus when the policy sets its URL, and from the kubelet otherwise
+func (r *ClusterReconciler) volumeStats(def *teranodev1alpha1.AutoExpandDef) VolumeStatsReader {
+	if def.PrometheusURL != "" {
+		return &PrometheusVolumeStats{URL: def.PrometheusURL}
+	}
+	return r.VolumeStats
+}
+
+// resizeInProgress is true while a previous expansion of the PVC hasn't reached its volume and filesystem yet
+func resizeInProgress(pvc *corev1.PersistentVolumeClaim) bool {
+	if pvc.Spec.Resources.Requests.Storage().Cmp(*pvc.Status.Capacity.Storage()) > 0 {
+		return true
+	}
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimResizing ||
+			condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending {
+			return true
+		}
+	}
+	return false
+}
+
+// autoExpandSize returns the size to grow the PVC to when its usage is above the threshold of its capacity and
+// it's below the maximum. The PVC grows by one step from its capacity.
+func autoExpandSize(def *teranodev1alpha1.AutoExpandDef, capacity *resource.Quantity, used int64) (resource.Quantity, bool) {
+	threshold := int64(def.ThresholdPercent)
+	if threshold <= 0 {
+		threshold = DefaultAutoExpandThresholdPercent
+	}
+	if capacity.Sign() <= 0 || used*100 < threshold*capacity.Value() || capacity.Cmp(def.Maximum) >= 0 {
+		return resource.Quantity{}, false
+	}
+	size := capacity.DeepCopy()
+	size.Add(def.Step)
+	if size.Cmp(def.Maximum) > 0 {
+		size = def.Maximum.DeepCopy()
+	}
+	return size, true
+}
+
+// setStorageMaximumCondition reports whether the shared PVC that grows automatically reached its maximum
+func (r *ClusterReconciler) setStorageMaximumCondition(cluster *teranodev1alpha1.Cluster) {
+	def := cluster.Spec.SharedStorage.AutoExpand
+	pvc := corev1.PersistentVolumeClaim{}
+	if def == nil || r.Get(r.Context, types.NamespacedName{Name: SharedPVCName, Namespace: cluster.Namespace}, &pvc) != nil {
+		apimeta.RemoveStatusCondition(&cluster.Status.Conditions, teranodev1alpha1.ConditionStorageMaximumReached)
+		return
+	}
+	size := pvc.Spec.Resources.Requests.Storage()
+	condition := metav1.Condition{
+		Type:    teranodev1alpha1.ConditionStorageMaximumReached,
+		Status:  metav1.ConditionFalse,
+		Reason:  teranodev1alpha1.StorageMaximumReasonBelow,
+		Message: fmt.Sprintf("%s is below the maximum of %s", size.String(), def.Maximum.String()),
+	}
+	if size.Cmp(def.Maximum) >= 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = teranodev1alpha1.StorageMaximumReasonReached
+		condition.Message = fmt.Sprintf("%s reached the maximum, raise autoExpand.maximum to keep growing", size.String())
+	}
+	apimeta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Storage auto-expansion", func() {
+	ctx := context.Background()
+
+	It("should expand the shared storage once per crossing, up to its maximum", func() {
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+			Provisioner:          "example.com/csi",
+			AllowVolumeExpansion: ptr.To(true),
+		}
+		Expect(k8sClient.Create(ctx, storageClass)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, storageClass)).To(Succeed())
+		})
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.SharedStorage = teranodev1alpha1.StorageConfig{
+				StorageClass: storageClass.Name,
+				StorageResources: &v1.VolumeResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceStorage: resource.MustParse("100Gi"),
+					},
+				},
+				AutoExpand: &teranodev1alpha1.AutoExpandDef{
+					ThresholdPercent: 80,
+					Step:             resource.MustParse("60Gi"),
+					Maximum:          resource.MustParse("200Gi"),
+				},
+			}
+		})
+
+		stats := &fakeVolumeStats{used: 90 << 30}
+		controllerReconciler := &ClusterReconciler{
+			Client:      k8sClient,
+			Scheme:      k8sClient.Scheme(),
+			VolumeStats: stats,
+		}
+		reconcileExpand := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cluster),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		reconcileExpand()
+
+		pvc := &v1.PersistentVolumeClaim{}
+		setCapacity := func(capacity string) {
+			Expect(getTestObject(ctx, cluster, SharedPVCName, pvc)).To(Succeed())
+			pvc.Status.Phase = v1.ClaimBound
+			pvc.Status.Capacity = v1.ResourceList{
+				v1.ResourceStorage: resource.MustParse(capacity),
+			}
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+		}
+		requested := func() string {
+			Expect(getTestObject(ctx, cluster, SharedPVCName, pvc)).To(Succeed())
+			return pvc.Spec.Resources.Requests.Storage().String()
+		}
+		setCapacity("100Gi")
+
+		// above the threshold, the PVC grows by a step
+		reconcileExpand()
+		Expect(requested()).To(Equal("160Gi"))
+
+		// it doesn't grow again until the volume is resized
+		reconcileExpand()
+		Expect(requested()).To(Equal("160Gi"))
+
+		// the resized volume is below the threshold
+		setCapacity("160Gi")
+		reconcileExpand()
+		Expect(requested()).To(Equal("160Gi"))
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(apimeta.IsStatusConditionFalse(cluster.Status.Conditions, teranodev1alpha1.ConditionStorageMaximumReached)).To(BeTrue())
+
+		// until the usage crosses it again, then it grows up to the maximum, where it stops
+		stats.used = 150 << 30
+		reconcileExpand()
+		Expect(requested()).To(Equal("200Gi"))
+		setCapacity("200Gi")
+		reconcileExpand()
+		Expect(requested()).To(Equal("200Gi"))
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(apimeta.IsStatusConditionTrue(cluster.Status.Conditions, teranodev1alpha1.ConditionStorageMaximumReached)).To(BeTrue())
+	})
+
+	It("should not grow while a resize is pending or below the threshold", func() {
+		pvc := &v1.PersistentVolumeClaim{}
+		pvc.Spec.Resources.Requests = v1.ResourceList{
+			v1.ResourceStorage: resource.MustParse("100Gi"),
+		}
+		pvc.Status.Capacity = v1.ResourceList{
+			v1.ResourceStorage: resource.MustParse("100Gi"),
+		}
+		pvc.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+			{
+				Type:   v1.PersistentVolumeClaimFileSystemResizePending,
+				Status: v1.ConditionTrue,
+			},
+		}
+		Expect(resizeInProgress(pvc)).To(BeTrue())
+
+		def := &teranodev1alpha1.AutoExpandDef{
+			ThresholdPercent: 80,
+			Step:             resource.MustParse("60Gi"),
+			Maximum:          resource.MustParse("200Gi"),
+		}
+		size, grow := autoExpandSize(def, ptr.To(resource.MustParse("100Gi")), 50<<30)
+		Expect(grow).To(BeFalse())
+		Expect(size.IsZero()).To(BeTrue())
+	})
+})
@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// VolumeSnapshotGVK is the CSI VolumeSnapshot kind. The snapshot CRDs are optional, so snapshots are
+// handled as unstructured objects and aren't watched.
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// DefaultBackupRetention is the number of snapshots kept for every PVC when the backup doesn't set one
+const DefaultBackupRetention = 7
+
+// backupApps are the apps whose PVCs are snapshotted: the shared PVC, and the PVCs of the stateful services
+var backupApps = []string{"cluster", "block-assembly", BlockchainServiceName, "block-persister", "legacy", UtxoPersisterName}
+
+// ReconcileBackup takes a VolumeSnapshot of every PVC of the cluster when the backup schedule is due, and deletes
+// the snapshots beyond the retention. When pausing block assembly, the snapshots wait for its pods to be gone.
+func (r *ClusterReconciler) ReconcileBackup(log logr.Logger) (bool, error) {
+	cluster := teranodev1alpha1.Cluster{}
+	if err := r.Get(r.Context, r.NamespacedName, &cluster); err != nil {
+		return false, err
+	}
+	def := cluster.Spec.Backup
+	if def == nil {
+		return true, nil
+	}
+	snapshots, err := r.listBackupSnapshots(&cluster)
+	if err != nil {
+		return false, err
+	}
+	due, err := backupDue(&cluster, snapshots, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if due {
+		if def.PauseBlockAssembly {
+			running, err := r.blockAssemblyRunning()
+			if err != nil {
+				return false, err
+			}
+			if running {
+				log.Info("waiting for block assembly to stop before taking snapshots")
+				return true, nil
+			}
+		}
+		if err := r.takeSnapshots(&cluster); err != nil {
+			return false, err
+		}
+		if snapshots, err = r.listBackupSnapshots(&cluster); err != nil {
+			return false, err
+		}
+	}
+	return true, r.pruneSnapshots(&cluster, snapshots)
+}
+
+// listBackupSnapshots returns the snapshots the operator took of the cluster
+func (r *ClusterReconciler) listBackupSnapshots(cluster *teranodev1alpha1.Cluster) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(VolumeSnapshotGVK.GroupVersion().WithKind(VolumeSnapshotGVK.Kind + "List"))
+	err := r.List(r.Context, list, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		teranodev1alpha1.ClusterLabel: cluster.Name,
+	})
+	if apimeta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("backups require the CSI snapshot CRDs: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// backupDue is true when the schedule has a run between the last snapshots, or the creation of the cluster, and now
+func backupDue(cluster *teranodev1alpha1.Cluster, snapshots []unstructured.Unstructured, now time.Time) (bool, error) {
+	schedule, err := utils.ParseCron(cluster.Spec.Backup.Schedule)
+	if err != nil {
+		return false, err
+	}
+	last := cluster.CreationTimestamp.Time
+	if t := lastSnapshotTime(snapshots); t != nil {
+		last = t.Time
+	}
+	next := schedule.Next(last.UTC())
+	return !next.IsZero() && !next.After(now.UTC()), nil
+}
+
+// lastSnapshotTime is when the newest snapshot was taken
+func lastSnapshotTime(snapshots []unstructured.Unstructured) *metav1.Time {
+	var last *metav1.Time
+	for _, snapshot := range snapshots {
+		created := snapshot.GetCreationTimestamp()
+		if last == nil || created.After(last.Time) {
+			last = &created
+		}
+	}
+	return last
+}
+
+// blockAssemblyPaused is true when block assembly is scaled down for the snapshots that are due, and until the
+// snapshots taken are cut. The VolumeSnapshot objects exist before the storage snapshots are, so block assembly
+// would otherwise write to the volumes while they are being snapshotted.
+func (r *ClusterReconciler) blockAssemblyPaused(cluster *teranodev1alpha1.Cluster) bool {
+	if cluster.Spec.Backup == nil || !cluster.Spec.Backup.PauseBlockAssembly {
+		return false
+	}
+	snapshots, err := r.listBackupSnapshots(cluster)
+	if err != nil {
+		return false
+	}
+	for _, snapshot := range snapshots {
+		if snapshotPending(&snapshot) {
+			return true
+		}
+	}
+	due, err := backupDue(cluster, snapshots, time.Now())
+	return err == nil && due
+}
+
+// snapshotPending is true until the storage snapshot is cut, which sets its creation time, or the snapshot fails
+func snapshotPending(snapshot *unstructured.Unstructured) bool {
+	if snapshot.GetDeletionTimestamp() != nil {
+		return false
+	}
+	if _, found, _ := unstructured.NestedString(snapshot.Object, "status", "creationTime"); found {
+		return false
+	}
+	if readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); readyToUse {
+		return false
+	}
+	_, failed, _ := unstructured.NestedFieldNoCopy(snapshot.Object, "status", "error")
+	return !failed
+}
+
+// blockAssemblyRunning is true while a block assembly pod exists
+func (r *ClusterReconciler) blockAssemblyRunning() (bool, error) {
+	pods := &corev1.PodList{}
+	err := r.List(r.Context, pods, client.InNamespace(r.NamespacedName.Namespace), client.MatchingLabels{
+		"app": "block-assembly",
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(pods.Items) > 0, nil
+}
+
+// takeSnapshots creates a VolumeSnapshot of every bound PVC of the cluster
+func (r *ClusterReconciler) takeSnapshots(cluster *teranodev1alpha1.Cluster) error {
+	apps, err := labels.NewRequirement("app", selection.In, backupApps)
+	if err != nil {
+		return err
+	}
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	err = r.List(r.Context, pvcs, client.InNamespace(cluster.Namespace), client.MatchingLabelsSelector{
+		Selector: labels.SelectorFromSet(labels.Set{teranodev1alpha1.TeranodeLabel: "true"}).Add(*apps),
+	})
+	if err != nil {
+		return err
+	}
+	suffix := time.Now().UTC().Format("20060102-150405")
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound || !pvc.DeletionTimestamp.IsZero() {
+			continue
+		}
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+		snapshot.SetName(fmt.Sprintf("%s-%s", pvc.Name, suffix))
+		snapshot.SetNamespace(cluster.Namespace)
+		snapshot.SetLabels(map[string]string{
+			teranodev1alpha1.TeranodeLabel:  "true",
+			teranodev1alpha1.ClusterLabel:   cluster.Name,
+			teranodev1alpha1.BackupPVCLabel: pvc.Name,
+		})
+		spec := map[string]interface{}{
+			"source": map[string]interface{}{
+				"persistentVolumeClaimName": pvc.Name,
+			},
+		}
+		if cluster.Spec.Backup.VolumeSnapshotClassName != "" {
+			spec["volumeSnapshotClassName"] = cluster.Spec.Backup.VolumeSnapshotClassName
+		}
+		if err := unstructured.SetNestedMap(snapshot.Object, spec, "spec"); err != nil {
+			return err
+		}
+		if err := r.Create(r.Context, snapshot); client.IgnoreAlreadyExists(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneSnapshots deletes the oldest snapshots of every PVC beyond the retention
+func (r *ClusterReconciler) pruneSnapshots(cluster *teranodev1alpha1.Cluster, snapshots []unstructured.Unstructured) error {
+	retention := int(cluster.Spec.Backup.Retention)
+	if retention <= 0 {
+		retention = DefaultBackupRetention
+	}
+	byPVC := map[string][]unstructured.Unstructured{}
+	for _, snapshot := range snapshots {
+		pvc := snapshot.GetLabels()[teranodev1alpha1.BackupPVCLabel]
+		byPVC[pvc] = append(byPVC[pvc], snapshot)
+	}
+	for _, pvcSnapshots := range byPVC {
+		sortSnapshots(pvcSnapshots)
+		for i := retention; i < len(pvcSnapshots); i++ {
+			if err := r.Delete(r.Context, &pvcSnapshots[i]); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortSnapshots sorts the snapshots from the newest
+func sortSnapshots(snapshots []unstructured.Unstructured) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		ti, tj := snapshots[i].GetCreationTimestamp(), snapshots[j].GetCreationTimestamp()
+		if ti.Equal(&tj) {
+			return snapshots[i].GetName() > snapshots[j].GetName()
+		}
+		return ti.After(tj.Time)
+	})
+}
+
+// backupStatus is the inventory of the snapshots of the cluster, from the newest
+func backupStatus(snapshots []unstructured.Unstructured) *teranodev1alpha1.BackupStatus {
+	status := &teranodev1alpha1.BackupStatus{
+		LastScheduleTime: lastSnapshotTime(snapshots),
+	}
+	sortSnapshots(snapshots)
+	for _, snapshot := range snapshots {
+		if snapshot.GetDeletionTimestamp() != nil {
+			continue
+		}
+		created := snapshot.GetCreationTimestamp()
+		s := teranodev1alpha1.SnapshotStatus{
+			Name:                  snapshot.GetName(),
+			PersistentVolumeClaim: snapshot.GetLabels()[teranodev1alpha1.BackupPVCLabel],
+			CreationTime:          &created,
+		}
+		if creationTime, found, _ := unstructured.NestedString(snapshot.Object, "status", "creationTime"); found {
+			if t, err := time.Parse(time.RFC3339, creationTime); err == nil {
+				s.CreationTime = &metav1.Time{Time: t}
+			}
+		}
+		s.ReadyToUse, _, _ = unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		s.RestoreSize, _, _ = unstructured.NestedString(snapshot.Object, "status", "restoreSize")
+		s.Error, _, _ = unstructured.NestedString(snapshot.Object, "status", "error", "message")
+		if s.ReadyToUse && (status.LastSuccessfulTime == nil || created.After(status.LastSuccessfulTime.Time)) {
+			status.LastSuccessfulTime = &created
+		}
+		status.Snapshots = append(status.Snapshots, s)
+	}
+	return status
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Backups", func() {
+	ctx := context.Background()
+
+	It("should take and prune snapshots of the cluster storage", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Backup = &teranodev1alpha1.BackupDef{
+				Schedule:  "@yearly",
+				Retention: 1,
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+
+		// the first backup is due a schedule after the cluster is created
+		due, err := backupDue(cluster, nil, cluster.CreationTimestamp.Time)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(due).To(BeFalse())
+		due, err = backupDue(cluster, nil, cluster.CreationTimestamp.AddDate(1, 0, 1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(due).To(BeTrue())
+
+		pvc := &v1.PersistentVolumeClaim{}
+		Expect(getTestObject(ctx, cluster, SharedPVCName, pvc)).To(Succeed())
+		pvc.Status.Phase = v1.ClaimBound
+		Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+
+		older := &unstructured.Unstructured{}
+		older.SetGroupVersionKind(VolumeSnapshotGVK)
+		older.SetName(SharedPVCName + "-00000000-000000")
+		older.SetNamespace(cluster.Namespace)
+		older.SetLabels(map[string]string{
+			teranodev1alpha1.ClusterLabel:   cluster.Name,
+			teranodev1alpha1.BackupPVCLabel: SharedPVCName,
+		})
+		Expect(unstructured.SetNestedField(older.Object, SharedPVCName, "spec", "source", "persistentVolumeClaimName")).To(Succeed())
+		Expect(k8sClient.Create(ctx, older)).To(Succeed())
+
+		controllerReconciler := &ClusterReconciler{
+			Client:         k8sClient,
+			Scheme:         k8sClient.Scheme(),
+			Context:        ctx,
+			NamespacedName: client.ObjectKeyFromObject(cluster),
+		}
+		Expect(controllerReconciler.takeSnapshots(cluster)).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+
+		// the snapshots beyond the retention are pruned, oldest first
+		snapshots := &unstructured.UnstructuredList{}
+		snapshots.SetGroupVersionKind(VolumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"))
+		Expect(k8sClient.List(ctx, snapshots, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+			teranodev1alpha1.BackupPVCLabel: SharedPVCName,
+		})).To(Succeed())
+		Expect(snapshots.Items).To(HaveLen(1))
+		Expect(snapshots.Items[0].GetName()).NotTo(Equal(older.GetName()))
+		source, _, _ := unstructured.NestedString(snapshots.Items[0].Object, "spec", "source", "persistentVolumeClaimName")
+		Expect(source).To(Equal(SharedPVCName))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(cluster.Status.Backup).NotTo(BeNil())
+		Expect(cluster.Status.Backup.LastScheduleTime).NotTo(BeNil())
+		Expect(cluster.Status.Backup.Snapshots).To(HaveLen(1))
+		Expect(cluster.Status.Backup.Snapshots[0].PersistentVolumeClaim).To(Equal(SharedPVCName))
+	})
+
+	It("should keep block assembly paused until the snapshots are cut", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Backup = &teranodev1alpha1.BackupDef{
+				Schedule:           "@yearly",
+				PauseBlockAssembly: true,
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		blockAssembly := &teranodev1alpha1.BlockAssembly{}
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "blockassembly"), blockAssembly)).To(Succeed())
+		Expect(blockAssembly.Spec.DeploymentOverrides.Replicas).To(BeNil())
+
+		// the snapshot exists, but its storage snapshot isn't cut yet
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+		snapshot.SetName(SharedPVCName + "-20261019-030000")
+		snapshot.SetNamespace(cluster.Namespace)
+		snapshot.SetLabels(map[string]string{
+			teranodev1alpha1.ClusterLabel:   cluster.Name,
+			teranodev1alpha1.BackupPVCLabel: SharedPVCName,
+		})
+		Expect(unstructured.SetNestedField(snapshot.Object, SharedPVCName, "spec", "source", "persistentVolumeClaimName")).To(Succeed())
+		Expect(k8sClient.Create(ctx, snapshot)).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "blockassembly"), blockAssembly)).To(Succeed())
+		Expect(blockAssembly.Spec.DeploymentOverrides.Replicas).To(Equal(ptr.To(int32(0))))
+
+		// block assembly resumes once the snapshot has its creation time
+		Expect(unstructured.SetNestedField(snapshot.Object, "2026-10-19T03:00:01Z", "status", "creationTime")).To(Succeed())
+		Expect(k8sClient.Status().Update(ctx, snapshot)).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "blockassembly"), blockAssembly)).To(Succeed())
+		Expect(blockAssembly.Spec.DeploymentOverrides.Replicas).To(BeNil())
+	})
+})
@@ -7,6 +7,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
@@ -70,6 +71,11 @@ func (r *ClusterReconciler) updateBlockAssembly(blockAssembly *teranodev1alpha1.
 	if cluster.Spec.ImagePullSecrets != nil {
 		blockAssembly.Spec.DeploymentOverrides.ImagePullSecrets = cluster.Spec.ImagePullSecrets
 	}
+	// scaled down while the snapshots of a backup are taken
+	if r.blockAssemblyPaused(cluster) {
+		blockAssembly.Spec.DeploymentOverrides = blockAssembly.Spec.DeploymentOverrides.DeepCopy()
+		blockAssembly.Spec.DeploymentOverrides.Replicas = ptr.To(int32(0))
+	}
 
 	return nil
 }
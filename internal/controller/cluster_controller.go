@@ -47,6 +47,8 @@ type ClusterReconciler struct {
 	NamespacedName types.NamespacedName
 	//nolint:containedctx // Required for reconciler pattern
 	Context context.Context
+	// VolumeStats reads the usage of the PVCs that grow automatically
+	VolumeStats VolumeStatsReader
 }
 
 //+kubebuilder:rbac:groups=teranode.bsvblockchain.org,resources=clusters,verbs=get;list;watch;create;update;patch;delete
@@ -77,24 +79,30 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	_, err := utils.ReconcileBatch(r.Log,
 		// r.Validate,
 		r.ReconcilePVC,
-		r.ReconcileAlertSystem,
-		r.ReconcileAsset,
-		r.ReconcileBlockAssembly,
-		r.ReconcileBlockPersister,
-		r.ReconcileBlockValidator,
-		r.ReconcileBlockchain,
-		r.ReconcileCoinbase,
-		r.ReconcileBootstrap,
-		r.ReconcileLegacy,
-		r.ReconcilePeer,
-		r.ReconcilePropagation,
-		r.ReconcileRPC,
-		r.ReconcileSubtreeValidator,
-		r.ReconcileUtxoPersister,
-		r.ReconcileValidator,
-		r.ReconcilePruner,
+		r.ReconcileAutoExpand,
+		r.ReconcileVolumes,
+		r.afterStorageRestored(
+			r.ReconcileAlertSystem,
+			r.ReconcileAsset,
+			r.ReconcileBlockAssembly,
+			r.ReconcileBlockPersister,
+			r.ReconcileBlockValidator,
+			r.ReconcileBlockchain,
+			r.ReconcileCoinbase,
+			r.ReconcileBootstrap,
+			r.ReconcileLegacy,
+			r.ReconcilePeer,
+			r.ReconcilePropagation,
+			r.ReconcileRPC,
+			r.ReconcileSubtreeValidator,
+			r.ReconcileUtxoPersister,
+			r.ReconcileValidator,
+			r.ReconcilePruner,
+		),
+		r.ReconcileInternalTLS,
 		r.ReconcileNetworkPolicy,
 		r.ReconcileAdditionalIngresses,
+		r.ReconcileBackup,
 	)
 	if err != nil {
 		apimeta.SetStatusCondition(&cluster.Status.Conditions,
@@ -121,6 +129,26 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		)
 	}
 
+	r.setStorageRestoredCondition(&cluster)
+	r.setStorageMaximumCondition(&cluster)
+
+	hostnames, err := clusterHostnames(ctx, r.Client, &cluster)
+	if err != nil {
+		r.Log.Error(err, "unable to list cluster hostnames")
+	} else {
+		cluster.Status.Hostnames = hostnames
+	}
+
+	cluster.Status.Backup = nil
+	if cluster.Spec.Backup != nil {
+		snapshots, err := r.listBackupSnapshots(&cluster)
+		if err != nil {
+			r.Log.Error(err, "unable to list cluster snapshots")
+		} else {
+			cluster.Status.Backup = backupStatus(snapshots)
+		}
+	}
+
 	err = r.Client.Status().Update(ctx, &cluster)
 	return ctrl.Result{RequeueAfter: 1 * time.Minute}, err
 }
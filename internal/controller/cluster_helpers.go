@@ -42,6 +42,12 @@ func mergeDeploymentOverrides(target *teranodev1alpha1.DeploymentOverrides, clus
 	if clusterOverrides.PodAntiAffinity != nil {
 		target.PodAntiAffinity = clusterOverrides.PodAntiAffinity
 	}
+	if clusterOverrides.Taints != nil {
+		target.Taints = clusterOverrides.Taints
+	}
+	if clusterOverrides.NodePool != "" {
+		target.NodePool = clusterOverrides.NodePool
+	}
 	if clusterOverrides.Strategy != nil {
 		target.Strategy = clusterOverrides.Strategy
 	}
@@ -60,4 +66,49 @@ func mergeDeploymentOverrides(target *teranodev1alpha1.DeploymentOverrides, clus
 	if clusterOverrides.ServiceAnnotations != nil {
 		target.ServiceAnnotations = clusterOverrides.ServiceAnnotations
 	}
+	if clusterOverrides.ServiceLabels != nil {
+		target.ServiceLabels = clusterOverrides.ServiceLabels
+	}
+	if clusterOverrides.IPFamilies != nil {
+		target.IPFamilies = clusterOverrides.IPFamilies
+	}
+	if clusterOverrides.IPFamilyPolicy != nil {
+		target.IPFamilyPolicy = clusterOverrides.IPFamilyPolicy
+	}
+	if clusterOverrides.Ingresses != nil {
+		target.Ingresses = clusterOverrides.Ingresses
+	}
+	if len(clusterOverrides.InitContainers) > 0 {
+		target.InitContainers = clusterOverrides.InitContainers
+	}
+	if len(clusterOverrides.SidecarContainers) > 0 {
+		target.SidecarContainers = clusterOverrides.SidecarContainers
+	}
+	if clusterOverrides.Scratch != nil {
+		target.Scratch = clusterOverrides.Scratch
+	}
+	if clusterOverrides.PodSecurityContext != nil {
+		target.PodSecurityContext = clusterOverrides.PodSecurityContext
+	}
+	if clusterOverrides.SecurityContext != nil {
+		target.SecurityContext = clusterOverrides.SecurityContext
+	}
+	if clusterOverrides.AutomountServiceAccountToken != nil {
+		target.AutomountServiceAccountToken = clusterOverrides.AutomountServiceAccountToken
+	}
+	if clusterOverrides.LivenessProbe != nil {
+		target.LivenessProbe = clusterOverrides.LivenessProbe
+	}
+	if clusterOverrides.ReadinessProbe != nil {
+		target.ReadinessProbe = clusterOverrides.ReadinessProbe
+	}
+	if clusterOverrides.StartupProbe != nil {
+		target.StartupProbe = clusterOverrides.StartupProbe
+	}
+	if clusterOverrides.PodLabels != nil {
+		target.PodLabels = clusterOverrides.PodLabels
+	}
+	if clusterOverrides.PodAnnotations != nil {
+		target.PodAnnotations = clusterOverrides.PodAnnotations
+	}
 }
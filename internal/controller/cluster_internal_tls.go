@@ -0,0 +1,351 @@
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// InternalCASecretName holds the CA signing the internal certificates when the cluster has no issuer
+const InternalCASecretName = "teranode-internal-ca"
+
+// Defaults of the internal certificates
+const (
+	DefaultInternalTLSDuration    = 90 * 24 * time.Hour
+	DefaultInternalTLSRenewBefore = 30 * 24 * time.Hour
+	internalCADuration            = 10 * 365 * 24 * time.Hour
+)
+
+// ErrInvalidInternalCA is returned when the CA secret doesn't hold a usable certificate and key
+var ErrInvalidInternalCA = errors.New("invalid internal CA secret")
+
+// tlsService is a service receiving an internal certificate
+type tlsService struct {
+	App     string
+	Service string
+	Kind    string
+	Suffix  string
+}
+
+// tlsServices are the services secured with mutual TLS, along with the resource the cluster creates for them
+var tlsServices = []tlsService{
+	{App: "alert", Service: "alert", Kind: "AlertSystem", Suffix: "alert-system"},
+	{App: AssetDeploymentName, Service: "asset", Kind: "Asset", Suffix: "asset"},
+	{App: "block-assembly", Service: "block-assembly", Kind: "BlockAssembly", Suffix: "blockassembly"},
+	{App: BlockchainServiceName, Service: BlockchainServiceName, Kind: "Blockchain", Suffix: "blockchain"},
+	{App: "block-persister", Service: "block-persister", Kind: "BlockPersister", Suffix: "blockpersister"},
+	{App: "block-validator", Service: BlockValidationDeploymentName, Kind: "BlockValidator", Suffix: "blockvalidator"},
+	{App: "coinbase", Service: "coinbase", Kind: "Coinbase", Suffix: "coinbase"},
+	{App: "legacy", Service: "legacy", Kind: "Legacy", Suffix: "legacy"},
+	{App: "peer", Service: "peer", Kind: "Peer", Suffix: "peer"},
+	{App: "propagation", Service: "propagation", Kind: "Propagation", Suffix: "propagation"},
+	{App: "pruner", Kind: "Pruner", Suffix: "pruner"},
+	{App: "rpc", Service: "rpc", Kind: "RPC", Suffix: "rpc"},
+	{App: "subtree-validator", Service: "subtree-validator", Kind: "SubtreeValidator", Suffix: "subtreevalidator"},
+	{App: "utxo-persister", Service: "utxo-persister", Kind: "UtxoPersister", Suffix: "utxo-persister"},
+	{App: "validator", Service: "validator", Kind: "Validator", Suffix: "validator"},
+}
+
+// dnsNames are the names the service is reached with inside the cluster
+func (s tlsService) dnsNames(namespace string) []string {
+	names := []string{s.App}
+	if s.Service != "" && s.Service != s.App {
+		names = append(names, s.Service)
+	}
+	dnsNames := []string{}
+	for _, name := range names {
+		dnsNames = append(dnsNames,
+			name,
+			fmt.Sprintf("%s.%s", name, namespace),
+			fmt.Sprintf("%s.%s.svc", name, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+		)
+	}
+	return dnsNames
+}
+
+func internalTLSDurations(def *teranodev1alpha1.InternalTLSDef) (time.Duration, time.Duration) {
+	duration, renewBefore := DefaultInternalTLSDuration, DefaultInternalTLSRenewBefore
+	if def.Duration != nil {
+		duration = def.Duration.Duration
+	}
+	if def.RenewBefore != nil {
+		renewBefore = def.RenewBefore.Duration
+	}
+	return duration, renewBefore
+}
+
+// ReconcileInternalTLS issues a certificate to every enabled service, and annotates its resource with the
+// checksum of the certificate so that its pods are restarted when the certificate is renewed
+func (r *ClusterReconciler) ReconcileInternalTLS(log logr.Logger) (bool, error) {
+	cluster := teranodev1alpha1.Cluster{}
+	if err := r.Get(r.Context, r.NamespacedName, &cluster); err != nil {
+		return false, err
+	}
+	def := cluster.Spec.InternalTLS
+	if def == nil {
+		return true, nil
+	}
+	var ca *corev1.Secret
+	if def.Issuer == nil {
+		var err error
+		if ca, err = r.reconcileInternalCA(&cluster); err != nil {
+			return false, err
+		}
+	}
+	apps := clusterApps(&cluster)
+	for _, service := range tlsServices {
+		if !apps[service.App] {
+			continue
+		}
+		var err error
+		if def.Issuer != nil {
+			err = r.reconcileInternalCertificate(&cluster, service)
+		} else {
+			err = r.reconcileInternalCertificateSecret(&cluster, service, ca)
+		}
+		if err != nil {
+			return false, err
+		}
+		if err := r.annotateInternalTLSChecksum(&cluster, service); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// reconcileInternalCA creates the CA signing the internal certificates, and renews it before it expires
+func (r *ClusterReconciler) reconcileInternalCA(cluster *teranodev1alpha1.Cluster) (*corev1.Secret, error) {
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      InternalCASecretName,
+			Namespace: cluster.Namespace,
+		},
+	}
+	_, renewBefore := internalTLSDurations(cluster.Spec.InternalTLS)
+	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &secret, func() error {
+		if err := controllerutil.SetControllerReference(cluster, &secret, r.Scheme); err != nil {
+			return err
+		}
+		secret.Type = corev1.SecretTypeTLS
+		if _, _, err := parseKeyPair(secret.Data); err == nil && !certificateExpiring(secret.Data, renewBefore) {
+			return nil
+		}
+		template := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: fmt.Sprintf("%s-%s-internal-ca", cluster.Namespace, cluster.Name)},
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		}
+		certPEM, keyPEM, err := issueCertificate(template, internalCADuration, nil, nil)
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		}
+		return nil
+	})
+	return &secret, err
+}
+
+// reconcileInternalCertificateSecret signs the certificate of a service with the CA of the operator. It's
+// reissued when it's about to expire or when the CA changed.
+func (r *ClusterReconciler) reconcileInternalCertificateSecret(cluster *teranodev1alpha1.Cluster, service tlsService, ca *corev1.Secret) error {
+	caCert, caKey, err := parseKeyPair(ca.Data)
+	if err != nil {
+		return err
+	}
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.InternalTLSSecretName(service.App),
+			Namespace: cluster.Namespace,
+		},
+	}
+	duration, renewBefore := internalTLSDurations(cluster.Spec.InternalTLS)
+	_, err = controllerutil.CreateOrUpdate(r.Context, r.Client, &secret, func() error {
+		if err := controllerutil.SetControllerReference(cluster, &secret, r.Scheme); err != nil {
+			return err
+		}
+		secret.Labels = getAppLabels(service.App)
+		secret.Type = corev1.SecretTypeTLS
+		if _, _, err := parseKeyPair(secret.Data); err == nil && !certificateExpiring(secret.Data, renewBefore) &&
+			string(secret.Data[corev1.ServiceAccountRootCAKey]) == string(ca.Data[corev1.TLSCertKey]) {
+			return nil
+		}
+		template := &x509.Certificate{
+			Subject:     pkix.Name{CommonName: service.App},
+			DNSNames:    service.dnsNames(cluster.Namespace),
+			KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		certPEM, keyPEM, err := issueCertificate(template, duration, caCert, caKey)
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:              certPEM,
+			corev1.TLSPrivateKeyKey:        keyPEM,
+			corev1.ServiceAccountRootCAKey: ca.Data[corev1.TLSCertKey],
+		}
+		return nil
+	})
+	return err
+}
+
+// reconcileInternalCertificate requests the certificate of a service from the cert-manager issuer of the cluster
+func (r *ClusterReconciler) reconcileInternalCertificate(cluster *teranodev1alpha1.Cluster, service tlsService) error {
+	def := cluster.Spec.InternalTLS
+	duration, renewBefore := internalTLSDurations(def)
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGVK)
+	cert.SetName(utils.InternalTLSSecretName(service.App))
+	cert.SetNamespace(cluster.Namespace)
+	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, cert, func() error {
+		if err := controllerutil.SetControllerReference(cluster, cert, r.Scheme); err != nil {
+			return err
+		}
+		cert.SetLabels(getAppLabels(service.App))
+		kind := def.Issuer.Kind
+		if kind == "" {
+			kind = "Issuer"
+		}
+		dnsNames := []interface{}{}
+		for _, name := range service.dnsNames(cluster.Namespace) {
+			dnsNames = append(dnsNames, name)
+		}
+		spec := map[string]interface{}{
+			"secretName":  utils.InternalTLSSecretName(service.App),
+			"commonName":  service.App,
+			"dnsNames":    dnsNames,
+			"duration":    duration.String(),
+			"renewBefore": renewBefore.String(),
+			"usages":      []interface{}{"digital signature", "key encipherment", "server auth", "client auth"},
+			"privateKey": map[string]interface{}{
+				"algorithm":      "ECDSA",
+				"size":           int64(256),
+				"rotationPolicy": "Always",
+			},
+			"issuerRef": map[string]interface{}{
+				"name":  def.Issuer.Name,
+				"kind":  kind,
+				"group": CertificateGVK.Group,
+			},
+		}
+		return unstructured.SetNestedMap(cert.Object, spec, "spec")
+	})
+	if apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("internal TLS requires cert-manager when an issuer is set: %w", err)
+	}
+	return err
+}
+
+// annotateInternalTLSChecksum sets the checksum of the certificate of a service on its resource, which sets it on its pods
+func (r *ClusterReconciler) annotateInternalTLSChecksum(cluster *teranodev1alpha1.Cluster, service tlsService) error {
+	secret := corev1.Secret{}
+	err := r.Get(r.Context, types.NamespacedName{Name: utils.InternalTLSSecretName(service.App), Namespace: cluster.Namespace}, &secret)
+	if err != nil {
+		// cert-manager hasn't issued the certificate yet
+		return client.IgnoreNotFound(err)
+	}
+	if len(secret.Data[corev1.TLSCertKey]) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(secret.Data[corev1.TLSCertKey])
+	checksum := hex.EncodeToString(sum[:])
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(teranodev1alpha1.GroupVersion.WithKind(service.Kind))
+	err = r.Get(r.Context, types.NamespacedName{Name: fmt.Sprintf("%s-%s", cluster.Name, service.Suffix), Namespace: cluster.Namespace}, obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if obj.Annotations[utils.InternalTLSChecksumAnnotation] == checksum {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopy())
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[utils.InternalTLSChecksumAnnotation] = checksum
+	return r.Patch(r.Context, obj, patch)
+}
+
+// issueCertificate creates a key and a certificate from the template, signed by the parent or self-signed without one
+func issueCertificate(template *x509.Certificate, duration time.Duration, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template.SerialNumber = serial
+	template.NotBefore = now.Add(-5 * time.Minute)
+	template.NotAfter = now.Add(duration)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// parseKeyPair returns the certificate and key of a TLS secret
+func parseKeyPair(data map[string][]byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(data[corev1.TLSCertKey])
+	keyBlock, _ := pem.Decode(data[corev1.TLSPrivateKeyKey])
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, ErrInvalidInternalCA
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// certificateExpiring returns whether the certificate of a TLS secret expires within the renewal window
+func certificateExpiring(data map[string][]byte, renewBefore time.Duration) bool {
+	cert, _, err := parseKeyPair(data)
+	return err != nil || time.Now().Add(renewBefore).After(cert.NotAfter)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+var _ = Describe("Internal TLS", func() {
+	ctx := context.Background()
+
+	It("should issue internal certificates and mount them", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.InternalTLS = &teranodev1alpha1.InternalTLSDef{}
+		})
+		reconcileTestCluster(ctx, cluster)
+
+		ca := &v1.Secret{}
+		Expect(getTestObject(ctx, cluster, InternalCASecretName, ca)).To(Succeed())
+		secret := &v1.Secret{}
+		Expect(getTestObject(ctx, cluster, utils.InternalTLSSecretName("block-validator"), secret)).To(Succeed())
+		Expect(secret.Data[v1.ServiceAccountRootCAKey]).To(Equal(ca.Data[v1.TLSCertKey]))
+		cert, _, err := parseKeyPair(secret.Data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.DNSNames).To(ContainElement("block-validation." + cluster.Namespace + ".svc"))
+
+		validator := &teranodev1alpha1.Validator{}
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "validator"), validator)).To(Succeed())
+		checksum := validator.Annotations[utils.InternalTLSChecksumAnnotation]
+		Expect(checksum).NotTo(BeEmpty())
+
+		_, err = reconcileTestService(ctx, &ValidatorReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "validator")
+		Expect(err).NotTo(HaveOccurred())
+		deployment := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "validator", deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Annotations[utils.InternalTLSChecksumAnnotation]).To(Equal(checksum))
+		Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      "internal-tls",
+			MountPath: utils.InternalTLSMountPath,
+			ReadOnly:  true,
+		}))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElement(v1.EnvVar{
+			Name:  utils.GRPCSecurityLevelSetting,
+			Value: utils.GRPCSecurityLevelMutualTLS,
+		}))
+
+		// a certificate within the renewal window is reissued, which rolls the pods
+		Expect(updateTestObject(ctx, cluster, func() {
+			cluster.Spec.InternalTLS.RenewBefore = &metav1.Duration{Duration: 24 * 365 * time.Hour}
+		})).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "validator"), validator)).To(Succeed())
+		Expect(validator.Annotations[utils.InternalTLSChecksumAnnotation]).NotTo(Equal(checksum))
+	})
+})
@@ -2,66 +2,317 @@ package controller
 
 import (
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
 )
 
-func defaultNetworkPolicySpec() *networkingv1.NetworkPolicySpec {
-	return &networkingv1.NetworkPolicySpec{
-		PodSelector: metav1.LabelSelector{
-			MatchLabels: getAppLabels(""),
-		},
-		PolicyTypes: []networkingv1.PolicyType{
-			networkingv1.PolicyTypeEgress,
+// NetworkPolicyPrefix is prepended to the app name for the NetworkPolicy of a service
+const NetworkPolicyPrefix = "teranode-"
+
+// DefaultIngressControllerNamespace may reach every service when the cluster doesn't list its ingress controllers
+const DefaultIngressControllerNamespace = "ingress-nginx"
+
+// legacyNetworkPolicyName is the single policy created before policies were generated per service
+const legacyNetworkPolicyName = "teranode"
+
+// serviceDependency allows the callers to reach the ports of an app
+type serviceDependency struct {
+	App     string
+	Ports   []int32
+	Callers []string
+}
+
+// serviceGraph declares which services call which. Each service only accepts these calls, and
+// may only call these ports, along with the ingress controllers and the internet traffic of the P2P services.
+var serviceGraph = []serviceDependency{
+	{
+		App:   BlockchainServiceName,
+		Ports: []int32{BlockchainGRPCPort, BlockchainHTTPPort},
+		Callers: []string{
+			"alert", AssetDeploymentName, "block-assembly", "block-persister", "block-validator", "bootstrap", "coinbase",
+			"legacy", "peer", "propagation", "pruner", "rpc", "subtree-validator", "utxo-persister", "validator",
 		},
-		Egress: []networkingv1.NetworkPolicyEgressRule{
-			{
-				To: []networkingv1.NetworkPolicyPeer{
-					{
-						NamespaceSelector: &metav1.LabelSelector{
-							MatchLabels: map[string]string{
-								"app": "blockchain",
-							},
-						},
-					},
-				},
-			},
+	},
+	{
+		App:   AssetDeploymentName,
+		Ports: []int32{AssetHTTPPort, AssetGRPCPort},
+		Callers: []string{
+			"block-persister", "block-validator", "coinbase", "legacy", "peer", "rpc", "subtree-validator", "utxo-persister",
 		},
+	},
+	{
+		App:     "block-assembly",
+		Ports:   []int32{BlockAssemblyPort},
+		Callers: []string{"block-validator", "legacy", "propagation", "rpc", "validator"},
+	},
+	{
+		App:     "block-validator",
+		Ports:   []int32{BlockValidationGRPCPort, BlockValidationHTTPPort},
+		Callers: []string{"legacy", "peer", "rpc"},
+	},
+	{
+		App:     "subtree-validator",
+		Ports:   []int32{SubtreeValidatorGRPCPort},
+		Callers: []string{"block-validator", "legacy", "peer"},
+	},
+	{
+		App:     "validator",
+		Ports:   []int32{ValidatorGRPCPort},
+		Callers: []string{"block-validator", "legacy", "propagation", "rpc", "subtree-validator"},
+	},
+	{
+		App:     "propagation",
+		Ports:   []int32{PropagationGRPCPort, PropagationHTTPPort},
+		Callers: []string{"coinbase", "legacy", "rpc"},
+	},
+	{
+		App:     "peer",
+		Ports:   []int32{PeerGRPCPort, PeerHTTPPort},
+		Callers: []string{AssetDeploymentName, "legacy", "rpc"},
+	},
+	{
+		App:     "coinbase",
+		Ports:   []int32{CoinbaseGRPCPort, CoinbaseHTTPPort},
+		Callers: []string{"rpc"},
+	},
+	{
+		App:     "legacy",
+		Ports:   []int32{LegacyHTTPPort},
+		Callers: []string{AssetDeploymentName, "rpc"},
+	},
+	{
+		App:     "alert",
+		Ports:   []int32{AlertWebserverPort},
+		Callers: []string{"rpc"},
+	},
+}
+
+// p2pPorts are the ports of the P2P services reachable from the internet. Only these services may reach the internet.
+var p2pPorts = map[string][]int32{
+	"alert":  {AlertSystemPort},
+	"legacy": {LegacyP2PPort},
+	"peer":   {PeerPort, PeerLegacyPort},
+}
+
+// clusterApps returns the app of every enabled service of the cluster
+func clusterApps(cluster *teranodev1alpha1.Cluster) map[string]bool {
+	return map[string]bool{
+		"alert":               cluster.Spec.AlertSystem.Enabled,
+		AssetDeploymentName:   cluster.Spec.Asset.Enabled,
+		"block-assembly":      cluster.Spec.BlockAssembly.Enabled,
+		BlockchainServiceName: cluster.Spec.Blockchain.Enabled,
+		"block-persister":     cluster.Spec.BlockPersister.Enabled,
+		"block-validator":     cluster.Spec.BlockValidator.Enabled,
+		"bootstrap":           cluster.Spec.Bootstrap.Enabled,
+		"coinbase":            cluster.Spec.Coinbase.Enabled,
+		"legacy":              cluster.Spec.Legacy.Enabled,
+		"peer":                cluster.Spec.Peer.Enabled,
+		"propagation":         cluster.Spec.Propagation.Enabled,
+		"rpc":                 cluster.Spec.RPC.Enabled,
+		"subtree-validator":   cluster.Spec.SubtreeValidator.Enabled,
+		"utxo-persister":      cluster.Spec.UtxoPersister.Enabled,
+		"validator":           cluster.Spec.Validator.Enabled,
+		"pruner":              cluster.Spec.Pruner.Enabled,
+	}
+}
+
+// exposedPorts are the ports of an app reachable from the internet through its exposure
+func exposedPorts(cluster *teranodev1alpha1.Cluster, app string) []int32 {
+	ports := p2pPorts[app]
+	switch app {
+	case "propagation":
+		if spec := cluster.Spec.Propagation.Spec; spec != nil && (spec.QuicExposure != nil || spec.QuicIngress != nil) {
+			ports = append(ports, PropagationQuicPort)
+		}
+	case "rpc":
+		if spec := cluster.Spec.RPC.Spec; spec != nil && spec.Exposure != nil {
+			ports = append(ports, RPCPort)
+		}
 	}
+	return ports
 }
 
-// ReconcileNetworkPolicy defines the network policy reconciler
+// ReconcileNetworkPolicy creates a NetworkPolicy for every enabled service when the cluster enforces them
 func (r *ClusterReconciler) ReconcileNetworkPolicy(log logr.Logger) (bool, error) {
 	cluster := teranodev1alpha1.Cluster{}
 	if err := r.Get(r.Context, r.NamespacedName, &cluster); err != nil {
 		return false, err
 	}
-	labels := getAppLabels("")
-	np := networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "teranode",
-			Namespace: r.NamespacedName.Namespace,
-			Labels:    labels,
-		},
-	}
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &np, func() error {
-		return r.updateNetworkPolicy(&np, &cluster)
-	})
-	if err != nil {
+	legacy := networkingv1.NetworkPolicy{}
+	err := r.Get(r.Context, types.NamespacedName{Name: legacyNetworkPolicyName, Namespace: r.NamespacedName.Namespace}, &legacy)
+	if client.IgnoreNotFound(err) != nil {
 		return false, err
 	}
+	if err == nil && metav1.IsControlledBy(&legacy, &cluster) {
+		if err := r.Delete(r.Context, &legacy); client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+	}
+
+	enforce := cluster.Spec.NetworkPolicy != nil && cluster.Spec.NetworkPolicy.Enforce
+	for app, enabled := range clusterApps(&cluster) {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      NetworkPolicyPrefix + app,
+				Namespace: r.NamespacedName.Namespace,
+				Labels:    getAppLabels(app),
+			},
+		}
+		if !enforce || !enabled || (cluster.Spec.Enabled != nil && !*cluster.Spec.Enabled) {
+			if err := r.Delete(r.Context, &np); client.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+			continue
+		}
+		_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &np, func() error {
+			return r.updateNetworkPolicy(&np, &cluster, app)
+		})
+		if err != nil {
+			return false, err
+		}
+	}
 	return true, nil
 }
 
-func (r *ClusterReconciler) updateNetworkPolicy(np *networkingv1.NetworkPolicy, cluster *teranodev1alpha1.Cluster) error {
+func (r *ClusterReconciler) updateNetworkPolicy(np *networkingv1.NetworkPolicy, cluster *teranodev1alpha1.Cluster, app string) error {
 	err := controllerutil.SetControllerReference(cluster, np, r.Scheme)
 	if err != nil {
 		return err
 	}
-	np.Spec = *defaultNetworkPolicySpec()
+	np.Spec = *networkPolicySpec(cluster, app)
 
 	return nil
 }
+
+func networkPolicySpec(cluster *teranodev1alpha1.Cluster, app string) *networkingv1.NetworkPolicySpec {
+	def := cluster.Spec.NetworkPolicy
+	spec := &networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app": app,
+			},
+		},
+		PolicyTypes: []networkingv1.PolicyType{
+			networkingv1.PolicyTypeIngress,
+			networkingv1.PolicyTypeEgress,
+		},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			// DNS
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					policyPort(corev1.ProtocolUDP, 53),
+					policyPort(corev1.ProtocolTCP, 53),
+				},
+			},
+		},
+	}
+
+	for _, dependency := range serviceGraph {
+		if dependency.App == app {
+			spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+				From:  []networkingv1.NetworkPolicyPeer{appsPeer(dependency.Callers)},
+				Ports: policyPorts(corev1.ProtocolTCP, dependency.Ports),
+			})
+		}
+		for _, caller := range dependency.Callers {
+			if caller == app {
+				spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{
+					To:    []networkingv1.NetworkPolicyPeer{appsPeer([]string{dependency.App})},
+					Ports: policyPorts(corev1.ProtocolTCP, dependency.Ports),
+				})
+			}
+		}
+	}
+
+	ingressControllers := def.IngressControllerNamespaces
+	if len(ingressControllers) == 0 {
+		ingressControllers = []string{DefaultIngressControllerNamespace}
+	}
+	spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+		From: []networkingv1.NetworkPolicyPeer{namespacesPeer(ingressControllers)},
+	})
+	if len(def.MonitoringNamespaces) > 0 {
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{namespacesPeer(def.MonitoringNamespaces)},
+		})
+	}
+
+	if ports := exposedPorts(cluster, app); len(ports) > 0 {
+		internetPorts := policyPorts(corev1.ProtocolTCP, ports)
+		if app == "propagation" {
+			// QUIC runs over UDP
+			internetPorts = policyPorts(corev1.ProtocolUDP, ports)
+		}
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  internetPeers(),
+			Ports: internetPorts,
+		})
+	}
+	if _, ok := p2pPorts[app]; ok {
+		spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To: internetPeers(),
+		})
+	}
+	spec.Egress = append(spec.Egress, def.Egress...)
+	return spec
+}
+
+func policyPort(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &intstr.IntOrString{Type: intstr.Int, IntVal: port},
+	}
+}
+
+func policyPorts(protocol corev1.Protocol, ports []int32) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		policyPorts = append(policyPorts, policyPort(protocol, port))
+	}
+	return policyPorts
+}
+
+// appsPeer selects the pods of the apps in the namespace of the policy
+func appsPeer(apps []string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "app",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   apps,
+				},
+			},
+		},
+	}
+}
+
+// namespacesPeer selects every pod of the namespaces
+func namespacesPeer(namespaces []string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      corev1.LabelMetadataName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   namespaces,
+				},
+			},
+		},
+	}
+}
+
+func internetPeers() []networkingv1.NetworkPolicyPeer {
+	return []networkingv1.NetworkPolicyPeer{
+		{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+		{IPBlock: &networkingv1.IPBlock{CIDR: "::/0"}},
+	}
+}
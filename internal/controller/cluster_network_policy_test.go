@@ -0,0 +1,96 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Network policies", func() {
+	ctx := context.Background()
+
+	storesEgress := networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{namespacesPeer([]string{"kafka", "aerospike"})},
+	}
+
+	It("should generate network policies from the service graph", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.NetworkPolicy = &teranodev1alpha1.NetworkPolicyDef{
+				Enforce:                     true,
+				IngressControllerNamespaces: []string{"traefik"},
+				Egress:                      []networkingv1.NetworkPolicyEgressRule{storesEgress},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+
+		np := &networkingv1.NetworkPolicy{}
+		Expect(getTestObject(ctx, cluster, NetworkPolicyPrefix+BlockchainServiceName, np)).To(Succeed())
+		Expect(np.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"app": BlockchainServiceName}))
+		Expect(np.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+		callers := np.Spec.Ingress[0].From[0].PodSelector.MatchExpressions[0].Values
+		Expect(callers).To(ContainElements("rpc", "peer", "validator"))
+		Expect(np.Spec.Ingress[0].Ports[0].Port.IntVal).To(Equal(int32(BlockchainGRPCPort)))
+		Expect(np.Spec.Ingress[1].From[0].NamespaceSelector.MatchExpressions[0].Values).To(ConsistOf("traefik"))
+
+		// propagation reaches the validator, which lets it in
+		toValidator := networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{appsPeer([]string{"validator"})},
+			Ports: policyPorts(v1.ProtocolTCP, []int32{ValidatorGRPCPort}),
+		}
+		Expect(getTestObject(ctx, cluster, NetworkPolicyPrefix+"propagation", np)).To(Succeed())
+		Expect(np.Spec.Egress).To(ContainElements(toValidator, storesEgress))
+		Expect(getTestObject(ctx, cluster, NetworkPolicyPrefix+"validator", np)).To(Succeed())
+		Expect(np.Spec.Ingress).To(ContainElement(networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{appsPeer([]string{"block-validator", "legacy", "propagation", "rpc", "subtree-validator"})},
+			Ports: policyPorts(v1.ProtocolTCP, []int32{ValidatorGRPCPort}),
+		}))
+
+		// only the P2P services reach the internet
+		internet := networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "::/0"}},
+			},
+		}
+		Expect(getTestObject(ctx, cluster, NetworkPolicyPrefix+"peer", np)).To(Succeed())
+		Expect(np.Spec.Egress).To(ContainElement(internet))
+		Expect(getTestObject(ctx, cluster, NetworkPolicyPrefix+"rpc", np)).To(Succeed())
+		Expect(np.Spec.Egress).NotTo(ContainElement(internet))
+
+		// turning enforcement off removes the policies
+		Expect(updateTestObject(ctx, cluster, func() {
+			cluster.Spec.NetworkPolicy.Enforce = false
+		})).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+		err := getTestObject(ctx, cluster, NetworkPolicyPrefix+"peer", np)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// the policies can't be enforced without egress to the stores
+		err = updateTestObject(ctx, cluster, func() {
+			cluster.Spec.NetworkPolicy = &teranodev1alpha1.NetworkPolicyDef{Enforce: true}
+		})
+		Expect(err).To(MatchError(ContainSubstring("enforced network policies need egress rules to the stores")))
+	})
+})
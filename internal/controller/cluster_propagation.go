@@ -74,6 +74,9 @@ func (r *ClusterReconciler) updatePropagation(propagation *teranodev1alpha1.Prop
 		if clusterSpec.QuicIngress != nil {
 			propagation.Spec.QuicIngress = clusterSpec.QuicIngress
 		}
+		if clusterSpec.QuicExposure != nil {
+			propagation.Spec.QuicExposure = clusterSpec.QuicExposure
+		}
 		if clusterSpec.GrpcIngress != nil {
 			propagation.Spec.GrpcIngress = clusterSpec.GrpcIngress
 		}
@@ -1,15 +1,27 @@
 package controller
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// Restore pod of the shared PVC
+const (
+	RestorePodName  = "storage-restore"
+	RestorePodImage = "registry.k8s.io/pause:3.10"
 )
 
 // ReconcilePVC is the postgres PVC
@@ -50,9 +62,90 @@ func (r *ClusterReconciler) ReconcilePVC(log logr.Logger) (bool, error) {
 	if err != nil && !k8serrors.IsForbidden(err) {
 		return false, err
 	}
+
+	// The services aren't started until the PVC restored from its data source is bound, see afterStorageRestored
+	restoring := storageRestoring(&pvc)
+	if err := r.reconcileRestorePod(&cluster, restoring); err != nil {
+		return false, err
+	}
+	if restoring {
+		log.Info("waiting for the shared PVC to be restored", "dataSource", pvc.Spec.DataSource.Name)
+	}
 	return true, nil
 }
 
+// afterStorageRestored runs the service reconcilers once the shared PVC is restored, so that the services don't
+// start on an empty volume, while the rest of the cluster is reconciled meanwhile
+func (r *ClusterReconciler) afterStorageRestored(reconcilers ...utils.ReconcileFunc) utils.ReconcileFunc {
+	return func(log logr.Logger) (bool, error) {
+		pvc := corev1.PersistentVolumeClaim{}
+		err := r.Get(r.Context, types.NamespacedName{Name: SharedPVCName, Namespace: r.NamespacedName.Namespace}, &pvc)
+		if client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+		if storageRestoring(&pvc) {
+			return true, nil
+		}
+		return utils.ReconcileBatch(log, reconcilers...)
+	}
+}
+
+// storageRestoring is true while a PVC provisioned from a data source isn't bound
+func storageRestoring(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.Spec.DataSource != nil && pvc.Status.Phase != corev1.ClaimBound
+}
+
+// reconcileRestorePod runs a pod mounting the shared PVC while it's being restored, since storage classes
+// that wait for the first consumer only provision the volume once a pod uses it. It's deleted once the PVC is bound.
+func (r *ClusterReconciler) reconcileRestorePod(cluster *teranodev1alpha1.Cluster, restoring bool) error {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RestorePodName,
+			Namespace: r.NamespacedName.Namespace,
+			Labels:    getAppLabels(RestorePodName),
+		},
+	}
+	if !restoring {
+		return client.IgnoreNotFound(r.Delete(r.Context, &pod))
+	}
+	err := r.Get(r.Context, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &pod)
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(cluster, &pod, r.Scheme); err != nil {
+		return err
+	}
+	pod.Spec = corev1.PodSpec{
+		AutomountServiceAccountToken: ptr.To(false),
+		SecurityContext:              defaultPodSecurityContext(),
+		Containers: []corev1.Container{
+			{
+				Name:            "restore",
+				Image:           RestorePodImage,
+				SecurityContext: defaultContainerSecurityContext(),
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						MountPath: "/data",
+						Name:      SharedPVCName,
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: SharedPVCName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: SharedPVCName,
+					},
+				},
+			},
+		},
+	}
+	return r.Create(r.Context, &pod)
+}
+
 func (r *ClusterReconciler) updatePVC(pvc, inClusterPVC *corev1.PersistentVolumeClaim, cluster *teranodev1alpha1.Cluster) error {
 	err := controllerutil.SetControllerReference(cluster, pvc, r.Scheme)
 	if err != nil {
@@ -70,11 +163,23 @@ func (r *ClusterReconciler) updatePVC(pvc, inClusterPVC *corev1.PersistentVolume
 	}
 	// If storage resources are configured, use them
 	if cluster.Spec.SharedStorage.StorageResources != nil {
-		pvc.Spec.Resources = *cluster.Spec.SharedStorage.StorageResources
+		pvc.Spec.Resources = *cluster.Spec.SharedStorage.StorageResources.DeepCopy()
+		// keep the size the PVC grew to automatically
+		if inClusterPVC != nil && cluster.Spec.SharedStorage.AutoExpand != nil &&
+			inClusterPVC.Spec.Resources.Requests.Storage().Cmp(*pvc.Spec.Resources.Requests.Storage()) > 0 {
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = inClusterPVC.Spec.Resources.Requests.Storage().DeepCopy()
+		}
 	}
 	if cluster.Spec.SharedStorage.StorageVolume != "" {
 		pvc.Spec.VolumeName = cluster.Spec.SharedStorage.StorageVolume
 	}
+	// the data source of a PVC can't change once it's created
+	if inClusterPVC == nil {
+		pvc.Spec.DataSource = cluster.Spec.SharedStorage.DataSource
+	}
 	return nil
 }
 
@@ -92,3 +197,26 @@ func defaultPVCSpec() *corev1.PersistentVolumeClaimSpec {
 		},
 	}
 }
+
+// setStorageRestoredCondition reports whether the shared PVC provisioned from a data source is bound
+func (r *ClusterReconciler) setStorageRestoredCondition(cluster *teranodev1alpha1.Cluster) {
+	pvc := corev1.PersistentVolumeClaim{}
+	err := r.Get(r.Context, types.NamespacedName{Name: SharedPVCName, Namespace: cluster.Namespace}, &pvc)
+	if err != nil || pvc.Spec.DataSource == nil {
+		apimeta.RemoveStatusCondition(&cluster.Status.Conditions, teranodev1alpha1.ConditionStorageRestored)
+		return
+	}
+	condition := metav1.Condition{
+		Type:    teranodev1alpha1.ConditionStorageRestored,
+		Status:  metav1.ConditionTrue,
+		Reason:  teranodev1alpha1.StorageRestoredReasonBound,
+		Message: fmt.Sprintf("restored from %s %s", pvc.Spec.DataSource.Kind, pvc.Spec.DataSource.Name),
+	}
+	if storageRestoring(&pvc) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = teranodev1alpha1.StorageRestoredReasonRestoring
+		condition.Message = fmt.Sprintf("restoring from %s %s, the services start once it's bound",
+			pvc.Spec.DataSource.Kind, pvc.Spec.DataSource.Name)
+	}
+	apimeta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Cluster storage", func() {
+	ctx := context.Background()
+
+	It("should start the services once the restored storage is bound", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.SharedStorage.DataSource = &v1.TypedLocalObjectReference{
+				APIGroup: ptr.To(VolumeSnapshotGVK.Group),
+				Kind:     VolumeSnapshotGVK.Kind,
+				Name:     "cluster-storage-20261019-030000",
+			}
+			cluster.Spec.NetworkPolicy = &teranodev1alpha1.NetworkPolicyDef{
+				Enforce: true,
+				Egress:  []networkingv1.NetworkPolicyEgressRule{{}},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+
+		pvc := &v1.PersistentVolumeClaim{}
+		Expect(getTestObject(ctx, cluster, SharedPVCName, pvc)).To(Succeed())
+		Expect(pvc.Spec.DataSource.Name).To(Equal("cluster-storage-20261019-030000"))
+		Expect(getTestObject(ctx, cluster, RestorePodName, &v1.Pod{})).To(Succeed())
+		err := k8sClient.Get(ctx, testServiceName(cluster, "blockchain"), &teranodev1alpha1.Blockchain{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		// the rest of the cluster is reconciled meanwhile
+		Expect(getTestObject(ctx, cluster, NetworkPolicyPrefix+BlockchainServiceName, &networkingv1.NetworkPolicy{})).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(apimeta.IsStatusConditionFalse(cluster.Status.Conditions, teranodev1alpha1.ConditionStorageRestored)).To(BeTrue())
+
+		// the services start once the PVC is bound
+		pvc.Status.Phase = v1.ClaimBound
+		Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "blockchain"), &teranodev1alpha1.Blockchain{})).To(Succeed())
+		err = getTestObject(ctx, cluster, RestorePodName, &v1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(apimeta.IsStatusConditionTrue(cluster.Status.Conditions, teranodev1alpha1.ConditionStorageRestored)).To(BeTrue())
+	})
+})
@@ -73,6 +73,23 @@ func (r *ClusterReconciler) updateRPC(rpc *teranodev1alpha1.RPC, cluster *terano
 			}
 			mergeDeploymentOverrides(rpc.Spec.DeploymentOverrides, clusterSpec.DeploymentOverrides)
 		}
+		if clusterSpec.Ingress != nil {
+			rpc.Spec.Ingress = clusterSpec.Ingress
+		}
+		if clusterSpec.Exposure != nil {
+			rpc.Spec.Exposure = clusterSpec.Exposure
+		}
+		if clusterSpec.Credentials != nil {
+			rpc.Spec.Credentials = clusterSpec.Credentials
+		}
+	}
+
+	// Rotating the credentials on the cluster rotates them on the rpc resource
+	if rotation, ok := cluster.Annotations[teranodev1alpha1.RotateCredentialsAnnotation]; ok {
+		if rpc.Annotations == nil {
+			rpc.Annotations = map[string]string{}
+		}
+		rpc.Annotations[teranodev1alpha1.RotateCredentialsAnnotation] = rotation
 	}
 
 	// Apply cluster-level defaults (only if not already set)
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// ReconcileVolumes creates the PVCs of the named volumes of the cluster. They are labeled as the shared PVC,
+// so that they are backed up with it. Removing a volume from the spec keeps its PVC and data.
+func (r *ClusterReconciler) ReconcileVolumes(log logr.Logger) (bool, error) {
+	cluster := teranodev1alpha1.Cluster{}
+	if err := r.Get(r.Context, r.NamespacedName, &cluster); err != nil {
+		return false, err
+	}
+	for _, volume := range cluster.Spec.Volumes {
+		if err := r.reconcileVolumePVC(&cluster, volume); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// reconcileVolumePVC creates the PVC of a volume. Like the shared PVC, the existing spec is kept so that only
+// the storage class and resources change, which resizes the volume once it's bound.
+func (r *ClusterReconciler) reconcileVolumePVC(cluster *teranodev1alpha1.Cluster, volume teranodev1alpha1.VolumeDef) error {
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.VolumePVCName(volume.Name),
+			Namespace: cluster.Namespace,
+			Labels:    getAppLabels("cluster"),
+		},
+	}
+	existingPVC := &corev1.PersistentVolumeClaim{}
+	err := r.Get(r.Context, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, existingPVC)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if k8serrors.IsNotFound(err) {
+		existingPVC = nil
+	}
+
+	_, err = controllerutil.CreateOrUpdate(r.Context, r.Client, &pvc, func() error {
+		if err := controllerutil.SetControllerReference(cluster, &pvc, r.Scheme); err != nil {
+			return err
+		}
+		if existingPVC == nil {
+			pvc.Spec = *volumePVCSpec(volume)
+			return nil
+		}
+		pvc.Spec = *existingPVC.Spec.DeepCopy()
+		if volume.StorageResources != nil && existingPVC.Status.Phase == corev1.ClaimBound {
+			pvc.Spec.Resources = *volume.StorageResources.DeepCopy()
+		}
+		return nil
+	})
+
+	// Ignore forbidden errors, such as shrinking the volume or a storage class that can't expand
+	if err != nil && !k8serrors.IsForbidden(err) {
+		return err
+	}
+	return nil
+}
+
+// volumePVCSpec is the spec of the PVC of a volume, ReadWriteMany and 100Gi unless set
+func volumePVCSpec(volume teranodev1alpha1.VolumeDef) *corev1.PersistentVolumeClaimSpec {
+	spec := &corev1.PersistentVolumeClaimSpec{
+		AccessModes: volume.AccessModes,
+		Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(DefaultServiceStorageSize),
+			},
+		},
+	}
+	if len(spec.AccessModes) == 0 {
+		spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+	}
+	if volume.StorageClass != "" {
+		spec.StorageClassName = &volume.StorageClass
+	}
+	if volume.StorageResources != nil {
+		spec.Resources = *volume.StorageResources.DeepCopy()
+	}
+	spec.DataSource = volume.DataSource
+	return spec
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+var _ = Describe("Store volumes", func() {
+	ctx := context.Background()
+
+	It("should place the stores on named volumes", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Volumes = []teranodev1alpha1.VolumeDef{
+				{
+					Name:   "fast",
+					Stores: []teranodev1alpha1.Store{teranodev1alpha1.StoreBlocks, teranodev1alpha1.StoreSubtrees},
+				},
+				{
+					Name:         "txs",
+					StorageClass: "gp3",
+					AccessModes:  []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+					StorageResources: &v1.VolumeResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceStorage: resource.MustParse("500Gi"),
+						},
+					},
+					Stores: []teranodev1alpha1.Store{teranodev1alpha1.StoreTxs},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+
+		pvc := &v1.PersistentVolumeClaim{}
+		Expect(getTestObject(ctx, cluster, utils.VolumePVCName("fast"), pvc)).To(Succeed())
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(v1.ReadWriteMany))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal(DefaultServiceStorageSize))
+		Expect(pvc.Labels).To(HaveKeyWithValue("app", "cluster"))
+		Expect(getTestObject(ctx, cluster, utils.VolumePVCName("txs"), pvc)).To(Succeed())
+		Expect(*pvc.Spec.StorageClassName).To(Equal("gp3"))
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(v1.ReadWriteOnce))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("500Gi"))
+
+		_, err := reconcileTestService(ctx, &BlockPersisterReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "blockpersister")
+		Expect(err).NotTo(HaveOccurred())
+		deployment := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "block-persister", deployment)).To(Succeed())
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.VolumeMounts).To(ContainElements(
+			v1.VolumeMount{
+				Name:      utils.VolumePVCName("fast"),
+				MountPath: "/data/subtreestore",
+				SubPath:   "subtrees",
+			},
+			v1.VolumeMount{
+				Name:      utils.VolumePVCName("txs"),
+				MountPath: "/data/txstore",
+				SubPath:   "txs",
+			},
+			v1.VolumeMount{
+				Name:      utils.VolumePVCName("fast"),
+				MountPath: "/data/blockstore",
+				SubPath:   "blocks",
+			},
+			// the dedicated storage of the block persister is mounted next to the block store
+			v1.VolumeMount{
+				Name:      servicePVCName("block-persister"),
+				MountPath: BlockPersisterDataPath,
+			},
+		))
+		Expect(container.Env).To(ContainElements(
+			v1.EnvVar{Name: "blockstore", Value: "file:///data/blockstore"},
+			v1.EnvVar{Name: "subtreestore", Value: "file:///data/subtreestore"},
+			v1.EnvVar{Name: "txstore", Value: "file:///data/txstore"},
+		))
+
+		// a store can only be on one volume
+		err = updateTestObject(ctx, cluster, func() {
+			cluster.Spec.Volumes[1].Stores = append(cluster.Spec.Volumes[1].Stores, teranodev1alpha1.StoreSubtrees)
+		})
+		Expect(err).To(MatchError(ContainSubstring("a store can only be on one volume")))
+	})
+})
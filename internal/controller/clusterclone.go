@@ -0,0 +1,538 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// VolumeSnapshotContentGVK is the CSI VolumeSnapshotContent kind. A PVC can only be provisioned from a snapshot in
+// its namespace, so a snapshot of the source is bound to a new content in the namespace of the clone.
+var VolumeSnapshotContentGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshotContent",
+}
+
+// errCloneNamespace is returned for a clone in the namespace of its source, since the PVC names would collide
+var errCloneNamespace = errors.New("a cluster can only be cloned into another namespace")
+
+// errCloneNotAllowed is returned when the source doesn't allow the namespace of the clone, since any clone could
+// otherwise read the storage of a cluster in another namespace
+var errCloneNotAllowed = fmt.Errorf("the source cluster doesn't allow clones into this namespace in its %s annotation",
+	teranodev1alpha1.CloneNamespacesAnnotation)
+
+// cloneIdentityFields are the fields of the spec of the source, and of the specs of its services, that identify
+// its endpoints: the PV its shared storage is bound to, its hosts, its exposures with their addresses, and the
+// Service annotations external-dns publishes them with. They're cleared so that the clone doesn't take over the
+// endpoints of the source.
+var cloneIdentityFields = []string{
+	"storageVolume",
+	"domain",
+	"additionalIngresses",
+	"serviceAnnotations",
+	"exposure",
+	"quicExposure",
+	"ingress",
+	"ingresses",
+}
+
+// ReconcileSnapshots snapshots the PVCs of the source cluster, and binds every snapshot that is ready to a
+// snapshot in the namespace of the clone. It's done once the clone is created.
+func (r *ClusterCloneReconciler) ReconcileSnapshots(log logr.Logger) (bool, error) {
+	clone := teranodev1alpha1.ClusterClone{}
+	if err := r.Get(r.Context, r.NamespacedName, &clone); err != nil {
+		return false, err
+	}
+	created, err := r.cloneCreated(&clone)
+	if err != nil || created {
+		return created, err
+	}
+	source, err := r.cloneSource(&clone)
+	if err != nil {
+		return false, err
+	}
+	pvcs, err := r.sourcePVCs(source)
+	if err != nil {
+		return false, err
+	}
+	ready := true
+	for _, pvc := range pvcs {
+		snapshot, err := r.reconcileSourceSnapshot(&clone, pvc.Name)
+		if err != nil {
+			return false, err
+		}
+		readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		contentName, _, _ := unstructured.NestedString(snapshot.Object, "status", "boundVolumeSnapshotContentName")
+		if !readyToUse || contentName == "" {
+			ready = false
+			continue
+		}
+		if err := r.reconcileCloneSnapshot(&clone, pvc.Name, contentName); err != nil {
+			return false, err
+		}
+	}
+	if !ready {
+		log.Info("waiting for the snapshots of the source cluster to be ready")
+	}
+	return ready, nil
+}
+
+// ReconcileCluster creates the clone from the spec of the source with the patch applied, its storage provisioned
+// from the snapshots. The clone isn't updated once it's created.
+func (r *ClusterCloneReconciler) ReconcileCluster(log logr.Logger) (bool, error) {
+	clone := teranodev1alpha1.ClusterClone{}
+	if err := r.Get(r.Context, r.NamespacedName, &clone); err != nil {
+		return false, err
+	}
+	created, err := r.cloneCreated(&clone)
+	if err != nil || created {
+		return created, err
+	}
+	source, err := r.cloneSource(&clone)
+	if err != nil {
+		return false, err
+	}
+	spec, err := cloneSpec(source, clone.Spec.Patch)
+	if err != nil {
+		return false, err
+	}
+	pvcs, err := r.sourcePVCs(source)
+	if err != nil {
+		return false, err
+	}
+	for _, pvc := range pvcs {
+		setCloneDataSource(spec, &pvc, &corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To(VolumeSnapshotGVK.Group),
+			Kind:     VolumeSnapshotGVK.Kind,
+			Name:     cloneSnapshotName(&clone, pvc.Name),
+		})
+	}
+	cluster := teranodev1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cloneClusterName(&clone),
+			Namespace: clone.Namespace,
+		},
+		Spec: *spec,
+	}
+	if err := controllerutil.SetControllerReference(&clone, &cluster, r.Scheme); err != nil {
+		return false, err
+	}
+	log.Info("creating the clone", "source", source.Namespace+"/"+source.Name, "volumes", len(pvcs))
+	return true, r.Create(r.Context, &cluster)
+}
+
+// cloneCreated is true once the clone exists
+func (r *ClusterCloneReconciler) cloneCreated(clone *teranodev1alpha1.ClusterClone) (bool, error) {
+	err := r.Get(r.Context, types.NamespacedName{Name: cloneClusterName(clone), Namespace: clone.Namespace}, &teranodev1alpha1.Cluster{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// cloneSource is the Cluster to clone
+func (r *ClusterCloneReconciler) cloneSource(clone *teranodev1alpha1.ClusterClone) (*teranodev1alpha1.Cluster, error) {
+	if clone.Spec.Source.Namespace == clone.Namespace {
+		return nil, errCloneNamespace
+	}
+	source := &teranodev1alpha1.Cluster{}
+	err := r.Get(r.Context, types.NamespacedName{Name: clone.Spec.Source.Name, Namespace: clone.Spec.Source.Namespace}, source)
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("source cluster %s/%s not found", clone.Spec.Source.Namespace, clone.Spec.Source.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cloneAllowed(source, clone.Namespace) {
+		return nil, errCloneNotAllowed
+	}
+	return source, nil
+}
+
+// cloneAllowed is true when the source lists the namespace in its clone namespaces annotation
+func cloneAllowed(source *teranodev1alpha1.Cluster, namespace string) bool {
+	for _, allowed := range strings.Split(source.Annotations[teranodev1alpha1.CloneNamespacesAnnotation], ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneClusterName is the name of the clone, the name of the source unless set
+func cloneClusterName(clone *teranodev1alpha1.ClusterClone) string {
+	if clone.Spec.ClusterName != "" {
+		return clone.Spec.ClusterName
+	}
+	return clone.Spec.Source.Name
+}
+
+// cloneSnapshotName is the snapshot a PVC of the source is cloned through, in the namespace of the clone
+func cloneSnapshotName(clone *teranodev1alpha1.ClusterClone, pvc string) string {
+	return fmt.Sprintf("%s-%s", clone.Name, pvc)
+}
+
+// sourceSnapshotName is the snapshot of a PVC of the source, in its namespace. It also names the content bound to the
+// snapshot of the clone, which isn't namespaced.
+func sourceSnapshotName(clone *teranodev1alpha1.ClusterClone, pvc string) string {
+	return fmt.Sprintf("%s-%s", clone.Namespace, cloneSnapshotName(clone, pvc))
+}
+
+// sourcePVCs are the bound PVCs of the source the clone provisions its storage from: the shared PVC, the named
+// volumes, and the dedicated storage of the stateful services, of their first replica as a StatefulSet
+func (r *ClusterCloneReconciler) sourcePVCs(source *teranodev1alpha1.Cluster) ([]corev1.PersistentVolumeClaim, error) {
+	apps, err := labels.NewRequirement("app", selection.In, backupApps)
+	if err != nil {
+		return nil, err
+	}
+	list := &corev1.PersistentVolumeClaimList{}
+	err = r.List(r.Context, list, client.InNamespace(source.Namespace), client.MatchingLabelsSelector{
+		Selector: labels.SelectorFromSet(labels.Set{teranodev1alpha1.TeranodeLabel: "true"}).Add(*apps),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pvcs := []corev1.PersistentVolumeClaim{}
+	for _, pvc := range list.Items {
+		if pvc.Status.Phase != corev1.ClaimBound || !pvc.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if strings.HasPrefix(pvc.Name, StatefulSetClaimName+"-") && !strings.HasSuffix(pvc.Name, "-0") {
+			continue
+		}
+		// skip the PVCs the spec of the source no longer uses, such as of a removed volume
+		if !setCloneDataSource(source.Spec.DeepCopy(), &pvc, &corev1.TypedLocalObjectReference{}) {
+			continue
+		}
+		pvcs = append(pvcs, pvc)
+	}
+	sort.Slice(pvcs, func(i, j int) bool { return pvcs[i].Name < pvcs[j].Name })
+	return pvcs, nil
+}
+
+// setCloneDataSource provisions the storage the PVC of the source holds from the data source, and is false when
+// the spec has no storage for the PVC
+func setCloneDataSource(spec *teranodev1alpha1.ClusterSpec, pvc *corev1.PersistentVolumeClaim, ref *corev1.TypedLocalObjectReference) bool {
+	switch pvc.Labels["app"] {
+	case "cluster":
+		if pvc.Name == SharedPVCName {
+			spec.SharedStorage.DataSource = ref
+			return true
+		}
+		for i := range spec.Volumes {
+			if utils.VolumePVCName(spec.Volumes[i].Name) == pvc.Name {
+				spec.Volumes[i].DataSource = ref
+				return true
+			}
+		}
+	case "block-assembly":
+		if spec.BlockAssembly.Spec != nil {
+			spec.BlockAssembly.Spec.StorageDataSource = ref
+			return true
+		}
+	case BlockchainServiceName:
+		if spec.Blockchain.Spec != nil {
+			spec.Blockchain.Spec.StorageDataSource = ref
+			return true
+		}
+	case "block-persister":
+		if spec.BlockPersister.Spec != nil {
+			spec.BlockPersister.Spec.StorageDataSource = ref
+			return true
+		}
+	case "legacy":
+		if spec.Legacy.Spec != nil {
+			spec.Legacy.Spec.StorageDataSource = ref
+			return true
+		}
+	case UtxoPersisterName:
+		if spec.UtxoPersister.Spec != nil {
+			spec.UtxoPersister.Spec.StorageDataSource = ref
+			return true
+		}
+	}
+	return false
+}
+
+// cloneSpec is the spec of the source without its endpoints, with the JSON merge patch applied
+func cloneSpec(source *teranodev1alpha1.Cluster, patch *runtime.RawExtension) (*teranodev1alpha1.ClusterSpec, error) {
+	fields := map[string]interface{}{}
+	doc, err := json.Marshal(source.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+	clearCloneIdentity(fields)
+	if doc, err = json.Marshal(fields); err != nil {
+		return nil, err
+	}
+	if patch != nil && len(patch.Raw) > 0 {
+		if doc, err = jsonpatch.MergePatch(doc, patch.Raw); err != nil {
+			return nil, fmt.Errorf("invalid clone patch: %w", err)
+		}
+	}
+	patched := &teranodev1alpha1.ClusterSpec{}
+	if err := json.Unmarshal(doc, patched); err != nil {
+		return nil, fmt.Errorf("invalid clone patch: %w", err)
+	}
+	return patched, nil
+}
+
+// clearCloneIdentity deletes the identity fields from the spec, its shared storage and the spec of every service
+func clearCloneIdentity(spec map[string]interface{}) {
+	for _, field := range cloneIdentityFields {
+		delete(spec, field)
+	}
+	if storage, ok := spec["sharedStorage"].(map[string]interface{}); ok {
+		delete(storage, "storageVolume")
+	}
+	for _, value := range spec {
+		config, _ := value.(map[string]interface{})
+		service, ok := config["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for field := range service {
+			if slices.Contains(cloneIdentityFields, field) || strings.HasSuffix(field, "Ingress") {
+				delete(service, field)
+			}
+		}
+	}
+}
+
+// reconcileSourceSnapshot creates the snapshot of a PVC of the source
+func (r *ClusterCloneReconciler) reconcileSourceSnapshot(clone *teranodev1alpha1.ClusterClone, pvc string) (*unstructured.Unstructured, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	err := r.Get(r.Context, types.NamespacedName{Name: sourceSnapshotName(clone, pvc), Namespace: clone.Spec.Source.Namespace}, snapshot)
+	if apimeta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("clones require the CSI snapshot CRDs: %w", err)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return snapshot, err
+	}
+	snapshot.SetName(sourceSnapshotName(clone, pvc))
+	snapshot.SetNamespace(clone.Spec.Source.Namespace)
+	snapshot.SetLabels(map[string]string{
+		teranodev1alpha1.TeranodeLabel:       "true",
+		teranodev1alpha1.CloneLabel:          clone.Name,
+		teranodev1alpha1.CloneNamespaceLabel: clone.Namespace,
+	})
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvc,
+		},
+	}
+	if clone.Spec.VolumeSnapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = clone.Spec.VolumeSnapshotClassName
+	}
+	if err := unstructured.SetNestedMap(snapshot.Object, spec, "spec"); err != nil {
+		return nil, err
+	}
+	return snapshot, r.Create(r.Context, snapshot)
+}
+
+// reconcileCloneSnapshot binds a new content, pointing to the storage snapshot of the source, to a snapshot in the
+// namespace of the clone. The content retains the storage snapshot, which belongs to the snapshot of the source.
+func (r *ClusterCloneReconciler) reconcileCloneSnapshot(clone *teranodev1alpha1.ClusterClone, pvc string, sourceContentName string) error {
+	sourceContent := &unstructured.Unstructured{}
+	sourceContent.SetGroupVersionKind(VolumeSnapshotContentGVK)
+	if err := r.Get(r.Context, types.NamespacedName{Name: sourceContentName}, sourceContent); err != nil {
+		return err
+	}
+	driver, _, _ := unstructured.NestedString(sourceContent.Object, "spec", "driver")
+	handle, _, _ := unstructured.NestedString(sourceContent.Object, "status", "snapshotHandle")
+	if driver == "" || handle == "" {
+		return fmt.Errorf("snapshot content %s has no driver or snapshot handle", sourceContentName)
+	}
+
+	content := &unstructured.Unstructured{}
+	content.SetGroupVersionKind(VolumeSnapshotContentGVK)
+	content.SetName(sourceSnapshotName(clone, pvc))
+	content.SetLabels(map[string]string{
+		teranodev1alpha1.TeranodeLabel:       "true",
+		teranodev1alpha1.CloneLabel:          clone.Name,
+		teranodev1alpha1.CloneNamespaceLabel: clone.Namespace,
+	})
+	spec := map[string]interface{}{
+		"deletionPolicy": "Retain",
+		"driver":         driver,
+		"source": map[string]interface{}{
+			"snapshotHandle": handle,
+		},
+		"volumeSnapshotRef": map[string]interface{}{
+			"name":      cloneSnapshotName(clone, pvc),
+			"namespace": clone.Namespace,
+		},
+	}
+	if class, ok, _ := unstructured.NestedString(sourceContent.Object, "spec", "volumeSnapshotClassName"); ok {
+		spec["volumeSnapshotClassName"] = class
+	}
+	if mode, ok, _ := unstructured.NestedString(sourceContent.Object, "spec", "sourceVolumeMode"); ok {
+		spec["sourceVolumeMode"] = mode
+	}
+	if err := unstructured.SetNestedMap(content.Object, spec, "spec"); err != nil {
+		return err
+	}
+	if err := r.Create(r.Context, content); client.IgnoreAlreadyExists(err) != nil {
+		return err
+	}
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetName(cloneSnapshotName(clone, pvc))
+	snapshot.SetNamespace(clone.Namespace)
+	snapshot.SetLabels(getAppLabels("cluster"))
+	if err := controllerutil.SetControllerReference(clone, snapshot, r.Scheme); err != nil {
+		return err
+	}
+	err := unstructured.SetNestedMap(snapshot.Object, map[string]interface{}{
+		"source": map[string]interface{}{
+			"volumeSnapshotContentName": content.GetName(),
+		},
+	}, "spec")
+	if err != nil {
+		return err
+	}
+	return client.IgnoreAlreadyExists(r.Create(r.Context, snapshot))
+}
+
+// listCloneSnapshots returns the snapshots the clone took of its source
+func (r *ClusterCloneReconciler) listCloneSnapshots(clone *teranodev1alpha1.ClusterClone) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(VolumeSnapshotGVK.GroupVersion().WithKind(VolumeSnapshotGVK.Kind + "List"))
+	err := r.List(r.Context, list, client.InNamespace(clone.Spec.Source.Namespace), client.MatchingLabels{
+		teranodev1alpha1.CloneLabel:          clone.Name,
+		teranodev1alpha1.CloneNamespaceLabel: clone.Namespace,
+	})
+	if apimeta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// deleteCloneSnapshots deletes the snapshots the clone took of its source, along with the storage snapshots, and
+// the contents that bound them to the namespace of the clone
+func (r *ClusterCloneReconciler) deleteCloneSnapshots(clone *teranodev1alpha1.ClusterClone) error {
+	snapshots, err := r.listCloneSnapshots(clone)
+	if err != nil {
+		return err
+	}
+	for i := range snapshots {
+		if err := r.Delete(r.Context, &snapshots[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	contents := &unstructured.UnstructuredList{}
+	contents.SetGroupVersionKind(VolumeSnapshotContentGVK.GroupVersion().WithKind(VolumeSnapshotContentGVK.Kind + "List"))
+	err = r.List(r.Context, contents, client.MatchingLabels{
+		teranodev1alpha1.CloneLabel:          clone.Name,
+		teranodev1alpha1.CloneNamespaceLabel: clone.Namespace,
+	})
+	if apimeta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := range contents.Items {
+		if err := r.Delete(r.Context, &contents.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clonedVolumes lists the PVCs of the source and the snapshots they are cloned through
+func clonedVolumes(snapshots []unstructured.Unstructured) []teranodev1alpha1.ClonedVolume {
+	volumes := []teranodev1alpha1.ClonedVolume{}
+	for _, snapshot := range snapshots {
+		pvc, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		volumes = append(volumes, teranodev1alpha1.ClonedVolume{
+			PersistentVolumeClaim: pvc,
+			Snapshot:              snapshot.GetName(),
+			ReadyToUse:            readyToUse,
+		})
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].PersistentVolumeClaim < volumes[j].PersistentVolumeClaim })
+	return volumes
+}
+
+// clonePhase is the progress of the clone: snapshotting until it's created, restoring until its shared storage
+// is bound, then starting until the Deployments and StatefulSets of its namespace are ready
+func (r *ClusterCloneReconciler) clonePhase(clone *teranodev1alpha1.ClusterClone) (teranodev1alpha1.ClonePhase, string, error) {
+	cluster := teranodev1alpha1.Cluster{}
+	err := r.Get(r.Context, types.NamespacedName{Name: cloneClusterName(clone), Namespace: clone.Namespace}, &cluster)
+	if k8serrors.IsNotFound(err) {
+		ready := 0
+		for _, volume := range clone.Status.Volumes {
+			if volume.ReadyToUse {
+				ready++
+			}
+		}
+		return teranodev1alpha1.ClonePhaseSnapshotting, fmt.Sprintf("%d of %d snapshots ready", ready, len(clone.Status.Volumes)), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if cluster.Spec.SharedStorage.DataSource != nil &&
+		!apimeta.IsStatusConditionTrue(cluster.Status.Conditions, teranodev1alpha1.ConditionStorageRestored) {
+		return teranodev1alpha1.ClonePhaseRestoring, "waiting for the shared storage to be restored", nil
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(r.Context, deployments, client.InNamespace(clone.Namespace), client.MatchingLabels{teranodev1alpha1.TeranodeLabel: "true"}); err != nil {
+		return "", "", err
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.List(r.Context, statefulSets, client.InNamespace(clone.Namespace), client.MatchingLabels{teranodev1alpha1.TeranodeLabel: "true"}); err != nil {
+		return "", "", err
+	}
+	total := len(deployments.Items) + len(statefulSets.Items)
+	ready := 0
+	for _, dep := range deployments.Items {
+		if dep.Status.ReadyReplicas >= ptr.Deref(dep.Spec.Replicas, 1) {
+			ready++
+		}
+	}
+	for _, sts := range statefulSets.Items {
+		if sts.Status.ReadyReplicas >= ptr.Deref(sts.Spec.Replicas, 1) {
+			ready++
+		}
+	}
+	if total == 0 || ready < total {
+		return teranodev1alpha1.ClonePhaseStarting, fmt.Sprintf("%d of %d services ready", ready, total), nil
+	}
+	return teranodev1alpha1.ClonePhaseServing, fmt.Sprintf("%d services ready", total), nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// ClusterCloneReconciler reconciles a ClusterClone object
+type ClusterCloneReconciler struct {
+	client.Client
+
+	Scheme         *runtime.Scheme
+	Log            logr.Logger
+	NamespacedName types.NamespacedName
+	//nolint:containedctx // Required for reconciler pattern
+	Context context.Context
+}
+
+//+kubebuilder:rbac:groups=teranode.bsvblockchain.org,resources=clusterclones,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=teranode.bsvblockchain.org,resources=clusterclones/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=teranode.bsvblockchain.org,resources=clusterclones/finalizers,verbs=update
+
+// Reconcile snapshots the storage of the source cluster, and creates the clone restored from the snapshots.
+// The clone is polled until it's serving, when the snapshots of the source are deleted.
+func (r *ClusterCloneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result := ctrl.Result{}
+	r.Log = log.FromContext(ctx).WithValues("clusterclone", req.NamespacedName)
+	r.Context = ctx
+	r.NamespacedName = req.NamespacedName
+	clone := teranodev1alpha1.ClusterClone{}
+	if err := r.Get(ctx, req.NamespacedName, &clone); err != nil {
+		r.Log.Error(err, "unable to fetch cluster clone CR")
+		return result, nil
+	}
+	if !clone.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&clone, teranodev1alpha1.CloneFinalizer) {
+			return result, nil
+		}
+		if err := r.deleteCloneSnapshots(&clone); err != nil {
+			return result, err
+		}
+		controllerutil.RemoveFinalizer(&clone, teranodev1alpha1.CloneFinalizer)
+		return result, r.Update(ctx, &clone)
+	}
+	if controllerutil.AddFinalizer(&clone, teranodev1alpha1.CloneFinalizer) {
+		if err := r.Update(ctx, &clone); err != nil {
+			return result, err
+		}
+	}
+
+	_, err := utils.ReconcileBatch(r.Log,
+		r.ReconcileSnapshots,
+		r.ReconcileCluster,
+	)
+	if err != nil {
+		apimeta.SetStatusCondition(&clone.Status.Conditions,
+			metav1.Condition{
+				Type:    teranodev1alpha1.ConditionReconciled,
+				Status:  metav1.ConditionFalse,
+				Reason:  teranodev1alpha1.ReconciledReasonError,
+				Message: err.Error(),
+			},
+		)
+		clone.Status.Phase = teranodev1alpha1.ClonePhaseFailed
+		clone.Status.Message = err.Error()
+		_ = r.Client.Status().Update(ctx, &clone)
+		// Since error is written on the status, let's log it and requeue
+		// Returning error here is redundant
+		r.Log.Error(err, "requeuing object for reconciliation")
+		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, nil
+	}
+	apimeta.SetStatusCondition(&clone.Status.Conditions,
+		metav1.Condition{
+			Type:    teranodev1alpha1.ConditionReconciled,
+			Status:  metav1.ConditionTrue,
+			Reason:  teranodev1alpha1.ReconciledReasonComplete,
+			Message: teranodev1alpha1.ReconcileCompleteMessage,
+		},
+	)
+
+	snapshots, err := r.listCloneSnapshots(&clone)
+	if err != nil {
+		r.Log.Error(err, "unable to list clone snapshots")
+	} else if len(snapshots) > 0 {
+		// the volumes are kept once the snapshots are deleted
+		clone.Status.Volumes = clonedVolumes(snapshots)
+	}
+	clone.Status.Phase, clone.Status.Message, err = r.clonePhase(&clone)
+	if err != nil {
+		return result, err
+	}
+	if clone.Status.Phase == teranodev1alpha1.ClonePhaseServing && len(snapshots) > 0 {
+		if err := r.deleteCloneSnapshots(&clone); err != nil {
+			r.Log.Error(err, "unable to delete clone snapshots")
+		}
+	}
+
+	err = r.Client.Status().Update(ctx, &clone)
+	if clone.Status.Phase == teranodev1alpha1.ClonePhaseServing {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterCloneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&teranodev1alpha1.ClusterClone{}).
+		Owns(&teranodev1alpha1.Cluster{}).
+		Complete(r)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("ClusterClone Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+
+		sourceName := types.NamespacedName{Name: "mainnet", Namespace: "clone-source"}
+		cloneName := types.NamespacedName{Name: "staging", Namespace: "clone-target"}
+
+		It("should clone the cluster through snapshots of its storage", func() {
+			for _, ns := range []string{sourceName.Namespace, cloneName.Namespace} {
+				Expect(k8sClient.Create(ctx, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: ns},
+				})).To(Succeed())
+			}
+			source := &teranodev1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      sourceName.Name,
+					Namespace: sourceName.Namespace,
+				},
+				Spec: teranodev1alpha1.ClusterSpec{
+					Image: "teranode:current",
+					SharedStorage: teranodev1alpha1.StorageConfig{
+						StorageVolume: "pv-mainnet",
+					},
+					Domain:             &teranodev1alpha1.DomainDef{Name: "mainnet.example.com"},
+					ServiceAnnotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "p2p.mainnet.example.com"},
+					Peer: teranodev1alpha1.PeerConfig{
+						Enabled: true,
+						Spec: &teranodev1alpha1.PeerSpec{
+							Exposure: &teranodev1alpha1.ExposureDef{
+								Type:               teranodev1alpha1.ExposureTypeLoadBalancer,
+								StaticIP:           "203.0.113.10",
+								AdvertiseAddresses: []string{"203.0.113.10"},
+							},
+						},
+					},
+					Propagation: teranodev1alpha1.PropagationConfig{
+						Enabled: true,
+						Spec: &teranodev1alpha1.PropagationSpec{
+							GrpcIngress: &teranodev1alpha1.IngressDef{Host: "propagation.mainnet.example.com"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, source)).To(Succeed())
+			clusterReconciler := &ClusterReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := clusterReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: sourceName})
+			Expect(err).NotTo(HaveOccurred())
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: SharedPVCName, Namespace: sourceName.Namespace}, pvc)).To(Succeed())
+			pvc.Status.Phase = corev1.ClaimBound
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+
+			clone := &teranodev1alpha1.ClusterClone{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      cloneName.Name,
+					Namespace: cloneName.Namespace,
+				},
+				Spec: teranodev1alpha1.ClusterCloneSpec{
+					Source: teranodev1alpha1.ClusterCloneSource{
+						Name:      sourceName.Name,
+						Namespace: sourceName.Namespace,
+					},
+					Patch: &runtime.RawExtension{
+						Raw: []byte(`{"image":"teranode:next","propagation":{"enabled":false},"domain":{"name":"staging.example.com"}}`),
+					},
+					VolumeSnapshotClassName: "csi-snapclass",
+				},
+			}
+			Expect(k8sClient.Create(ctx, clone)).To(Succeed())
+			controllerReconciler := &ClusterCloneReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: cloneName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// the source has to allow clones into the namespace
+			Expect(k8sClient.Get(ctx, cloneName, clone)).To(Succeed())
+			Expect(clone.Status.Phase).To(Equal(teranodev1alpha1.ClonePhaseFailed))
+			Expect(clone.Status.Message).To(Equal(errCloneNotAllowed.Error()))
+			Expect(clone.Status.Volumes).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, sourceName, source)).To(Succeed())
+			source.Annotations = map[string]string{teranodev1alpha1.CloneNamespacesAnnotation: "other, " + cloneName.Namespace}
+			Expect(k8sClient.Update(ctx, source)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: cloneName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// the PVCs of the source are snapshotted first
+			Expect(k8sClient.Get(ctx, cloneName, clone)).To(Succeed())
+			Expect(clone.Status.Phase).To(Equal(teranodev1alpha1.ClonePhaseSnapshotting))
+			Expect(clone.Finalizers).To(ContainElement(teranodev1alpha1.CloneFinalizer))
+			Expect(clone.Status.Volumes).To(ConsistOf(teranodev1alpha1.ClonedVolume{
+				PersistentVolumeClaim: SharedPVCName,
+				Snapshot:              "clone-target-staging-cluster-storage",
+			}))
+			snapshot := &unstructured.Unstructured{}
+			snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "clone-target-staging-cluster-storage",
+				Namespace: sourceName.Namespace,
+			}, snapshot)).To(Succeed())
+			class, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+			Expect(class).To(Equal("csi-snapclass"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: sourceName.Name, Namespace: cloneName.Namespace},
+				&teranodev1alpha1.Cluster{})).NotTo(Succeed())
+
+			// as the snapshot controller would, bind the snapshot to its content
+			sourceContent := &unstructured.Unstructured{}
+			sourceContent.SetGroupVersionKind(VolumeSnapshotContentGVK)
+			sourceContent.SetName("snapcontent-mainnet")
+			sourceContent.Object["spec"] = map[string]interface{}{
+				"deletionPolicy":          "Delete",
+				"driver":                  "ebs.csi.aws.com",
+				"volumeSnapshotClassName": "csi-snapclass",
+				"source": map[string]interface{}{
+					"volumeHandle": "vol-123",
+				},
+				"volumeSnapshotRef": map[string]interface{}{
+					"name":      snapshot.GetName(),
+					"namespace": snapshot.GetNamespace(),
+				},
+			}
+			sourceContent.Object["status"] = map[string]interface{}{
+				"snapshotHandle": "snap-123",
+				"readyToUse":     true,
+			}
+			Expect(k8sClient.Create(ctx, sourceContent)).To(Succeed())
+			Expect(unstructured.SetNestedMap(snapshot.Object, map[string]interface{}{
+				"readyToUse":                     true,
+				"boundVolumeSnapshotContentName": sourceContent.GetName(),
+			}, "status")).To(Succeed())
+			Expect(k8sClient.Status().Update(ctx, snapshot)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: cloneName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// the storage snapshot is bound to a snapshot in the namespace of the clone
+			content := &unstructured.Unstructured{}
+			content.SetGroupVersionKind(VolumeSnapshotContentGVK)
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "clone-target-staging-cluster-storage"}, content)).To(Succeed())
+			handle, _, _ := unstructured.NestedString(content.Object, "spec", "source", "snapshotHandle")
+			Expect(handle).To(Equal("snap-123"))
+			policy, _, _ := unstructured.NestedString(content.Object, "spec", "deletionPolicy")
+			Expect(policy).To(Equal("Retain"))
+			ref, _, _ := unstructured.NestedStringMap(content.Object, "spec", "volumeSnapshotRef")
+			Expect(ref).To(Equal(map[string]string{"name": "staging-cluster-storage", "namespace": cloneName.Namespace}))
+			cloneSnapshot := &unstructured.Unstructured{}
+			cloneSnapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      "staging-cluster-storage",
+				Namespace: cloneName.Namespace,
+			}, cloneSnapshot)).To(Succeed())
+			contentName, _, _ := unstructured.NestedString(cloneSnapshot.Object, "spec", "source", "volumeSnapshotContentName")
+			Expect(contentName).To(Equal(content.GetName()))
+
+			// the clone has the patched spec, its storage provisioned from the snapshot
+			cluster := &teranodev1alpha1.Cluster{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: sourceName.Name, Namespace: cloneName.Namespace}, cluster)).To(Succeed())
+			Expect(cluster.Spec.Image).To(Equal("teranode:next"))
+			Expect(cluster.Spec.Propagation.Enabled).To(BeFalse())
+			Expect(cluster.Spec.SharedStorage.DataSource).NotTo(BeNil())
+			Expect(cluster.Spec.SharedStorage.DataSource.Kind).To(Equal("VolumeSnapshot"))
+			Expect(cluster.Spec.SharedStorage.DataSource.Name).To(Equal("staging-cluster-storage"))
+			// without the endpoints of the source, the patch setting its own
+			Expect(cluster.Spec.SharedStorage.StorageVolume).To(BeEmpty())
+			Expect(cluster.Spec.Domain.Name).To(Equal("staging.example.com"))
+			Expect(cluster.Spec.ServiceAnnotations).To(BeEmpty())
+			Expect(cluster.Spec.Peer.Enabled).To(BeTrue())
+			Expect(cluster.Spec.Peer.Spec.Exposure).To(BeNil())
+			Expect(cluster.Spec.Propagation.Spec.GrpcIngress).To(BeNil())
+			Expect(metav1.IsControlledBy(cluster, clone)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, cloneName, clone)).To(Succeed())
+			Expect(clone.Status.Phase).To(Equal(teranodev1alpha1.ClonePhaseRestoring))
+
+			// a clone in the namespace of its source fails
+			sameNamespace := &teranodev1alpha1.ClusterClone{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "same-namespace",
+					Namespace: sourceName.Namespace,
+				},
+				Spec: teranodev1alpha1.ClusterCloneSpec{
+					Source: teranodev1alpha1.ClusterCloneSource{
+						Name:      sourceName.Name,
+						Namespace: sourceName.Namespace,
+					},
+					ClusterName: "copy",
+				},
+			}
+			Expect(k8sClient.Create(ctx, sameNamespace)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: sameNamespace.Name, Namespace: sameNamespace.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: sameNamespace.Name, Namespace: sameNamespace.Namespace}, sameNamespace)).To(Succeed())
+			Expect(sameNamespace.Status.Phase).To(Equal(teranodev1alpha1.ClonePhaseFailed))
+			Expect(k8sClient.Delete(ctx, sameNamespace)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: sameNamespace.Name, Namespace: sameNamespace.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// deleting the clone deletes the snapshot of the source and the content bound to the clone
+			Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, clone)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: cloneName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, cloneName, clone))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{
+				Name:      snapshot.GetName(),
+				Namespace: snapshot.GetNamespace(),
+			}, snapshot))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: content.GetName()}, content))).To(BeTrue())
+			Expect(k8sClient.Delete(ctx, source)).To(Succeed())
+		})
+	})
+})
@@ -74,6 +74,7 @@ func (r *CoinbaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	_, err := utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
 		r.ReconcileGrpcIngress,
 	)
 
@@ -102,7 +103,17 @@ func (r *CoinbaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		)
 	}
 
+	certificates, certErr := certificateStatuses(ctx, r.Client, &coinbase)
+	if certErr != nil {
+		r.Log.Error(certErr, "unable to fetch certificates")
+	}
+	coinbase.Status.Certificates = certificates
+
 	err = r.Client.Status().Update(ctx, &coinbase)
+	if !certificatesReady(coinbase.Status.Certificates) {
+		return ctrl.Result{RequeueAfter: CertificateRequeueInterval}, err
+	}
+
 	return ctrl.Result{Requeue: false, RequeueAfter: 0}, err
 }
 
@@ -78,6 +78,7 @@ func defaultCoinbaseDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -86,6 +87,8 @@ func defaultCoinbaseDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultCoinbaseImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "coinbase",
+						SecurityContext: defaultContainerSecurityContext(),
+						VolumeMounts:    []corev1.VolumeMount{tmpVolumeMount()},
 						// Make sane defaults, and this should be configurable
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -96,40 +99,9 @@ func defaultCoinbaseDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("2Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(CoinbaseHTTPPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(CoinbaseHTTPPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						StartupProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(CoinbaseHTTPPort),
-								},
-							},
-							FailureThreshold: 30,
-							PeriodSeconds:    10,
-						},
+						ReadinessProbe: defaultReadinessProbe(CoinbaseHTTPPort),
+						LivenessProbe:  defaultLivenessProbe(CoinbaseHTTPPort),
+						StartupProbe:   defaultStartupProbe(CoinbaseHTTPPort, DefaultStartupWindow),
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: DebuggerPort,
@@ -158,7 +130,7 @@ func defaultCoinbaseDeploymentSpec() *appsv1.DeploymentSpec {
 						},
 					},
 				},
-				Volumes: []corev1.Volume{},
+				Volumes: []corev1.Volume{tmpVolume()},
 			},
 		},
 	}
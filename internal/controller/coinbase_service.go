@@ -39,6 +39,7 @@ func (r *CoinbaseReconciler) updateService(svc *corev1.Service, coinbase *terano
 		return err
 	}
 	svc.Spec = *defaultCoinbaseServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, coinbase)
 	return nil
 }
 
@@ -88,3 +89,15 @@ func defaultCoinbaseServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *CoinbaseReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	coinbase := teranodev1alpha1.Coinbase{}
+	if err := r.Get(r.Context, r.NamespacedName, &coinbase); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &coinbase, "coinbase", "coinbase"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
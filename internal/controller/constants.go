@@ -1,6 +1,8 @@
 // Package controller implements all reconciler logic
 package controller
 
+import "time"
+
 // DefaultServiceAccountName defines the name of the service acount created by the bundle
 const DefaultServiceAccountName = "teranode-operator-service-runner"
 
@@ -13,6 +15,15 @@ const DefaultImage = "434394763103.dkr.ecr.eu-north-1.amazonaws.com/teranode-pub
 // DefaultCoinbaseImage is the default coinbase service image
 const DefaultCoinbaseImage = "434394763103.dkr.ecr.eu-north-1.amazonaws.com/teranode-coinbase:v0.1.0"
 
+// Teranode settings set by the operator
+
+const (
+	P2PListenAddressesSetting    = "p2p_listen_addresses"
+	P2PAdvertiseAddressesSetting = "p2p_advertise_addresses"
+	RPCUserSetting               = "rpc_user"
+	RPCPassSetting               = "rpc_pass"
+)
+
 // Service Names
 
 const BlockchainServiceName = "blockchain"
@@ -44,7 +55,9 @@ const (
 	PropagationQuicPort      = 8384
 	RPCPort                  = 9292
 	SubtreeValidatorGRPCPort = 8086
+	ValidatorGRPCPort        = 8081
 	LegacyHTTPPort           = 8098
+	LegacyP2PPort            = 8333
 	ProfilerPort             = 9091
 	DebuggerPort             = 4040
 	HealthPort               = 8000
@@ -79,3 +92,13 @@ const (
 	DefaultSubtreeValidatorReplicas = 2
 	DefaultAlertSystemReplicas      = 1
 )
+
+// Startup windows, i.e. how long a service may take to answer its liveness endpoint after it starts, such as while
+// opening its stores or replaying its state, before it's restarted. They don't cover the catch-up once it's alive:
+// the liveness probe takes over then, and only the readiness probe fails while the service is syncing.
+const (
+	DefaultStartupWindow    = 5 * time.Minute
+	SyncStartupWindow       = 30 * time.Minute
+	BlockchainStartupWindow = time.Hour
+	LegacyStartupWindow     = 4 * time.Hour
+)
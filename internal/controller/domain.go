@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// external-dns annotations set on the endpoints of clusters that opt in
+const (
+	ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	ExternalDNSTTLAnnotation      = "external-dns.alpha.kubernetes.io/ttl"
+)
+
+// endpointCluster returns the cluster owning the service, or nil when it isn't part of one
+func endpointCluster(ctx context.Context, c client.Client, owner serviceOwner) *teranodev1alpha1.Cluster {
+	cluster := utils.GetClusterOwner(c, ctx, owner.Metadata())
+	if cluster == nil || cluster.Name == "" {
+		return nil
+	}
+	return cluster
+}
+
+// domainHost derives the host of an endpoint from the domain of the cluster. It is empty when the cluster has no domain.
+func domainHost(cluster *teranodev1alpha1.Cluster, service string) string {
+	if cluster == nil || cluster.Spec.Domain == nil || cluster.Spec.Domain.Name == "" {
+		return ""
+	}
+	template := cluster.Spec.Domain.HostTemplate
+	if template == "" {
+		template = teranodev1alpha1.DefaultHostTemplate
+	}
+	return strings.NewReplacer(
+		"{{service}}", service,
+		"{{cluster}}", cluster.Name,
+		"{{namespace}}", cluster.Namespace,
+		"{{domain}}", cluster.Spec.Domain.Name,
+	).Replace(template)
+}
+
+// resolveIngressHost returns the ingress definition with its host derived from the cluster domain when it has none
+func resolveIngressHost(cluster *teranodev1alpha1.Cluster, def *teranodev1alpha1.IngressDef, name string) *teranodev1alpha1.IngressDef {
+	if def.Host != "" {
+		return def
+	}
+	host := domainHost(cluster, name)
+	if host == "" {
+		return def
+	}
+	resolved := *def
+	resolved.Host = host
+	return &resolved
+}
+
+// setEndpointHostname labels an endpoint with its cluster, and adds the external-dns annotations when the cluster opts in
+func setEndpointHostname(obj metav1.Object, cluster *teranodev1alpha1.Cluster, host string) {
+	if cluster == nil || host == "" {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[teranodev1alpha1.ClusterLabel] = cluster.Name
+	obj.SetLabels(labels)
+
+	if cluster.Spec.Domain == nil || cluster.Spec.Domain.ExternalDNS == nil {
+		return
+	}
+	externalDNS := cluster.Spec.Domain.ExternalDNS
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range externalDNS.Annotations {
+		annotations[k] = v
+	}
+	annotations[ExternalDNSHostnameAnnotation] = host
+	if externalDNS.TTL != nil {
+		annotations[ExternalDNSTTLAnnotation] = strconv.Itoa(int(*externalDNS.TTL))
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// clusterHostnames returns the hosts of the Ingresses, Gateway API routes and Services labeled with the cluster
+func clusterHostnames(ctx context.Context, c client.Client, cluster *teranodev1alpha1.Cluster) ([]teranodev1alpha1.EndpointHostname, error) {
+	opts := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{teranodev1alpha1.ClusterLabel: cluster.Name},
+	}
+	hostnames := []teranodev1alpha1.EndpointHostname{}
+
+	ingresses := networkingv1.IngressList{}
+	if err := c.List(ctx, &ingresses, opts...); err != nil {
+		return nil, err
+	}
+	for _, ingress := range ingresses.Items {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				hostnames = append(hostnames, teranodev1alpha1.EndpointHostname{Name: ingress.Name, Kind: "Ingress", Host: rule.Host})
+			}
+		}
+	}
+
+	services := corev1.ServiceList{}
+	if err := c.List(ctx, &services, opts...); err != nil {
+		return nil, err
+	}
+	for _, svc := range services.Items {
+		if host := svc.Annotations[ExternalDNSHostnameAnnotation]; host != "" {
+			hostnames = append(hostnames, teranodev1alpha1.EndpointHostname{Name: svc.Name, Kind: "Service", Host: host})
+		}
+	}
+
+	routeLists := map[string]client.ObjectList{
+		"HTTPRoute": &gatewayv1.HTTPRouteList{},
+		"GRPCRoute": &gatewayv1.GRPCRouteList{},
+		"TLSRoute":  &gatewayv1alpha2.TLSRouteList{},
+	}
+	for kind, list := range routeLists {
+		if err := c.List(ctx, list, opts...); err != nil {
+			// the Gateway API isn't installed
+			if apimeta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		items, err := apimeta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			route, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			for _, host := range routeHostnames(route) {
+				hostnames = append(hostnames, teranodev1alpha1.EndpointHostname{Name: route.GetName(), Kind: kind, Host: host})
+			}
+		}
+	}
+
+	sort.Slice(hostnames, func(i, j int) bool {
+		if hostnames[i].Host != hostnames[j].Host {
+			return hostnames[i].Host < hostnames[j].Host
+		}
+		return hostnames[i].Name < hostnames[j].Name
+	})
+	return hostnames, nil
+}
+
+func routeHostnames(route client.Object) []string {
+	var hostnames []gatewayv1.Hostname
+	switch route := route.(type) {
+	case *gatewayv1.HTTPRoute:
+		hostnames = route.Spec.Hostnames
+	case *gatewayv1.GRPCRoute:
+		hostnames = route.Spec.Hostnames
+	case *gatewayv1alpha2.TLSRoute:
+		hostnames = route.Spec.Hostnames
+	}
+	hosts := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		hosts = append(hosts, string(hostname))
+	}
+	return hosts
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Cluster domain", func() {
+	ctx := context.Background()
+
+	It("should derive hosts from the cluster domain", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Domain = &teranodev1alpha1.DomainDef{
+				Name: "example.com",
+				ExternalDNS: &teranodev1alpha1.ExternalDNSDef{
+					TTL: ptr.To(int32(300)),
+				},
+			}
+			cluster.Spec.Propagation.Spec = &teranodev1alpha1.PropagationSpec{
+				GrpcIngress: &teranodev1alpha1.IngressDef{},
+			}
+			cluster.Spec.Peer.Spec = &teranodev1alpha1.PeerSpec{
+				Exposure: &teranodev1alpha1.ExposureDef{},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &PropagationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = reconcileTestService(ctx, &PeerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+
+		propagationHost := fmt.Sprintf("propagation-grpc.%s.example.com", cluster.Name)
+		ingress := &networkingv1.Ingress{}
+		Expect(getTestObject(ctx, cluster, "propagation-grpc", ingress)).To(Succeed())
+		Expect(ingress.Spec.Rules[0].Host).To(Equal(propagationHost))
+		Expect(ingress.Annotations).To(HaveKeyWithValue(ExternalDNSHostnameAnnotation, propagationHost))
+		Expect(ingress.Annotations).To(HaveKeyWithValue(ExternalDNSTTLAnnotation, "300"))
+
+		peerHost := fmt.Sprintf("peer.%s.example.com", cluster.Name)
+		svc := &v1.Service{}
+		Expect(getTestObject(ctx, cluster, "peer"+ExternalServiceSuffix, svc)).To(Succeed())
+		Expect(svc.Annotations).To(HaveKeyWithValue(ExternalDNSHostnameAnnotation, peerHost))
+
+		// the cluster reports the hosts of its endpoints
+		reconcileTestCluster(ctx, cluster)
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		Expect(cluster.Status.Hostnames).To(ContainElements(
+			teranodev1alpha1.EndpointHostname{Name: "propagation-grpc", Kind: "Ingress", Host: propagationHost},
+			teranodev1alpha1.EndpointHostname{Name: "peer" + ExternalServiceSuffix, Kind: "Service", Host: peerHost},
+		))
+	})
+})
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// ExternalServiceSuffix is appended to the app name for the service exposing its P2P ports
+const ExternalServiceSuffix = "-external"
+
+// serviceOwner is the custom resource owning the objects generated for a service
+type serviceOwner interface {
+	client.Object
+	teranodev1alpha1.TeranodeService
+}
+
+func exposureType(exposure *teranodev1alpha1.ExposureDef) teranodev1alpha1.ExposureType {
+	if exposure.Type == "" {
+		return teranodev1alpha1.ExposureTypeLoadBalancer
+	}
+	return exposure.Type
+}
+
+// exposureUsesService is true when the exposure goes through a LoadBalancer or NodePort service
+func exposureUsesService(exposure *teranodev1alpha1.ExposureDef) bool {
+	if exposure == nil {
+		return false
+	}
+	t := exposureType(exposure)
+	return t == teranodev1alpha1.ExposureTypeLoadBalancer || t == teranodev1alpha1.ExposureTypeNodePort
+}
+
+// reconcileExposureService creates the external service for the P2P ports of an app,
+// or deletes it when the exposure doesn't need one
+func reconcileExposureService(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner,
+	exposure *teranodev1alpha1.ExposureDef, app string, ports []corev1.ServicePort) error {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app + ExternalServiceSuffix,
+			Namespace: owner.GetNamespace(),
+			Labels:    getAppLabels(app),
+		},
+	}
+	if !exposureUsesService(exposure) {
+		return client.IgnoreNotFound(c.Delete(ctx, &svc))
+	}
+	cluster := endpointCluster(ctx, c, owner)
+	_, err := controllerutil.CreateOrUpdate(ctx, c, &svc, func() error {
+		if err := controllerutil.SetControllerReference(owner, &svc, scheme); err != nil {
+			return err
+		}
+		updateExposureService(&svc, exposure, app, ports)
+		utils.SetServiceIPFamilies(c, &svc, owner)
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			setEndpointHostname(&svc, cluster, domainHost(cluster, app))
+		}
+		return nil
+	})
+	return err
+}
+
+func updateExposureService(svc *corev1.Service, exposure *teranodev1alpha1.ExposureDef, app string, ports []corev1.ServicePort) {
+	svc.Spec.Type = corev1.ServiceType(exposureType(exposure))
+	svc.Spec.Selector = map[string]string{
+		"app": app,
+	}
+	svc.Spec.Ports = make([]corev1.ServicePort, 0, len(ports))
+	for _, port := range ports {
+		if nodePort, ok := exposure.NodePorts[port.Name]; ok {
+			port.NodePort = nodePort
+		} else {
+			// keep the node port that was allocated on a previous reconcile
+			port.NodePort = existingNodePort(svc, port.Name)
+		}
+		svc.Spec.Ports = append(svc.Spec.Ports, port)
+	}
+	if exposure.ExternalTrafficPolicy != "" {
+		svc.Spec.ExternalTrafficPolicy = exposure.ExternalTrafficPolicy
+	}
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		// the annotations only apply to load balancers
+		utils.ReplaceAnnotations(svc, nil)
+		return
+	}
+	svc.Spec.LoadBalancerSourceRanges = exposure.LoadBalancerSourceRanges
+	svc.Spec.LoadBalancerClass = exposure.LoadBalancerClass
+	annotations := map[string]string{}
+	for k, v := range exposure.Annotations {
+		annotations[k] = v
+	}
+	if exposure.StaticIP != "" {
+		if exposure.StaticIPAnnotation != "" {
+			annotations[exposure.StaticIPAnnotation] = exposure.StaticIP
+		} else {
+			svc.Spec.LoadBalancerIP = exposure.StaticIP
+		}
+	}
+	utils.ReplaceAnnotations(svc, annotations)
+}
+
+func existingNodePort(svc *corev1.Service, name string) int32 {
+	for _, port := range svc.Spec.Ports {
+		if port.Name == name {
+			return port.NodePort
+		}
+	}
+	return 0
+}
+
+// setHostExposure binds the P2P ports on the node for the HostPort and HostNetwork exposure types. A new pod
+// can't bind the ports while the old one holds them on the same node, so the pods are recreated rather than
+// rolled. As a StatefulSet, the ordered rolling update of the workload already replaces one pod at a time.
+func setHostExposure(dep *appsv1.Deployment, exposure *teranodev1alpha1.ExposureDef, ports []corev1.ServicePort) {
+	podSpec := &dep.Spec.Template.Spec
+	if exposure == nil || len(podSpec.Containers) == 0 {
+		return
+	}
+	switch exposureType(exposure) {
+	case teranodev1alpha1.ExposureTypeHostNetwork:
+		podSpec.HostNetwork = true
+		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	case teranodev1alpha1.ExposureTypeHostPort:
+	default:
+		return
+	}
+	dep.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RecreateDeploymentStrategyType,
+	}
+	container := &podSpec.Containers[0]
+	for _, port := range ports {
+		for i := range container.Ports {
+			if container.Ports[i].ContainerPort == port.TargetPort.IntVal {
+				container.Ports[i].HostPort = port.TargetPort.IntVal
+			}
+		}
+	}
+}
+
+// exposureAddresses returns the host:port pairs the P2P ports are reachable on from outside the cluster
+func exposureAddresses(ctx context.Context, c client.Client, namespace string,
+	exposure *teranodev1alpha1.ExposureDef, app string, ports []corev1.ServicePort) ([]string, error) {
+	if exposure == nil {
+		return nil, nil
+	}
+	addresses := []string{}
+	switch exposureType(exposure) {
+	case teranodev1alpha1.ExposureTypeLoadBalancer:
+		svc := corev1.Service{}
+		err := c.Get(ctx, types.NamespacedName{Name: app + ExternalServiceSuffix, Namespace: namespace}, &svc)
+		if err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			host := ingress.IP
+			if host == "" {
+				host = ingress.Hostname
+			}
+			for _, port := range svc.Spec.Ports {
+				if hasServicePort(ports, port.Name) {
+					addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(int(port.Port))))
+				}
+			}
+		}
+	case teranodev1alpha1.ExposureTypeNodePort:
+		svc := corev1.Service{}
+		err := c.Get(ctx, types.NamespacedName{Name: app + ExternalServiceSuffix, Namespace: namespace}, &svc)
+		if err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		hosts, err := nodeAddresses(ctx, c, namespace, app)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			for _, port := range svc.Spec.Ports {
+				if hasServicePort(ports, port.Name) {
+					addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(int(port.NodePort))))
+				}
+			}
+		}
+	case teranodev1alpha1.ExposureTypeHostPort, teranodev1alpha1.ExposureTypeHostNetwork:
+		hosts, err := nodeAddresses(ctx, c, namespace, app)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			for _, port := range ports {
+				addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(int(port.TargetPort.IntVal))))
+			}
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+func hasServicePort(ports []corev1.ServicePort, name string) bool {
+	for _, port := range ports {
+		if port.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeAddresses returns the external address, or the internal one if it has none, of every node running a pod of the app
+func nodeAddresses(ctx context.Context, c client.Client, namespace string, app string) ([]string, error) {
+	pods := corev1.PodList{}
+	if err := c.List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabels{"app": app}); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	hosts := []string{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || seen[pod.Spec.NodeName] {
+			continue
+		}
+		seen[pod.Spec.NodeName] = true
+		node := corev1.Node{}
+		if err := c.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, &node); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if host := nodeAddress(&node); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
+
+func nodeAddress(node *corev1.Node) string {
+	internal := ""
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeExternalIP:
+			return address.Address
+		case corev1.NodeInternalIP:
+			if internal == "" {
+				internal = address.Address
+			}
+		}
+	}
+	return internal
+}
+
+// setP2PListenAddresses makes the service listen on the IP families of its Kubernetes service
+func setP2PListenAddresses(podSpec *corev1.PodSpec, families []corev1.IPFamily, policy *corev1.IPFamilyPolicy, setting string) {
+	if len(podSpec.Containers) == 0 || !utils.HasIPFamily(families, policy, corev1.IPv6Protocol) {
+		return
+	}
+	listen := []string{}
+	if len(families) == 0 || utils.HasIPFamily(families, policy, corev1.IPv4Protocol) {
+		listen = append(listen, "0.0.0.0")
+	}
+	listen = append(listen, "::")
+	setEnvDefault(&podSpec.Containers[0], setting, strings.Join(listen, ","))
+}
+
+// setEnvDefault sets an env var on the container unless the user already set it
+func setEnvDefault(container *corev1.Container, name string, value string) {
+	if hasEnv(container, name) {
+		return
+	}
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  name,
+		Value: value,
+	})
+}
+
+func hasEnv(container *corev1.Container, name string) bool {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
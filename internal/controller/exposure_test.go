@@ -0,0 +1,115 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Exposure", func() {
+	ctx := context.Background()
+
+	It("should expose the P2P ports outside the cluster", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Peer.Spec = &teranodev1alpha1.PeerSpec{
+				Exposure: &teranodev1alpha1.ExposureDef{
+					ExternalTrafficPolicy:    v1.ServiceExternalTrafficPolicyLocal,
+					LoadBalancerSourceRanges: []string{"203.0.113.0/24"},
+					StaticIP:                 "198.51.100.10",
+					StaticIPAnnotation:       "metallb.universe.tf/loadBalancerIPs",
+				},
+			}
+			cluster.Spec.Legacy.Spec = &teranodev1alpha1.LegacySpec{
+				Exposure: &teranodev1alpha1.ExposureDef{
+					Type: teranodev1alpha1.ExposureTypeHostNetwork,
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		peerReconciler := &PeerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+
+		svc := &v1.Service{}
+		Expect(getTestObject(ctx, cluster, "peer"+ExternalServiceSuffix, svc)).To(Succeed())
+		Expect(svc.Spec.Type).To(Equal(v1.ServiceTypeLoadBalancer))
+		Expect(svc.Spec.ExternalTrafficPolicy).To(Equal(v1.ServiceExternalTrafficPolicyLocal))
+		Expect(svc.Spec.LoadBalancerSourceRanges).To(ConsistOf("203.0.113.0/24"))
+		Expect(svc.Annotations).To(HaveKeyWithValue("metallb.universe.tf/loadBalancerIPs", "198.51.100.10"))
+		Expect(svc.Spec.Ports).To(HaveLen(2))
+
+		// the static IP annotation is removed with the static IP
+		peer := &teranodev1alpha1.Peer{}
+		peer.Name, peer.Namespace = testServiceName(cluster, "peer").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, peer, func() {
+			peer.Spec.Exposure.StaticIP = ""
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTestObject(ctx, cluster, "peer"+ExternalServiceSuffix, svc)).To(Succeed())
+		Expect(svc.Annotations).NotTo(HaveKey("metallb.universe.tf/loadBalancerIPs"))
+
+		// host ports can't be shared by two pods, so host-exposed pods are recreated rather than rolled
+		_, err = reconcileTestService(ctx, &LegacyReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "legacy")
+		Expect(err).NotTo(HaveOccurred())
+		dep := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "legacy", dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		Expect(dep.Spec.Template.Spec.DNSPolicy).To(Equal(v1.DNSClusterFirstWithHostNet))
+		Expect(dep.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+		err = getTestObject(ctx, cluster, "legacy"+ExternalServiceSuffix, &v1.Service{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should apply IP families and P2P addresses", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			dualStack := v1.IPFamilyPolicyPreferDualStack
+			cluster.Spec.IPFamilyPolicy = &dualStack
+			cluster.Spec.Peer.Spec = &teranodev1alpha1.PeerSpec{
+				Exposure: &teranodev1alpha1.ExposureDef{
+					Type:               teranodev1alpha1.ExposureTypeNodePort,
+					AdvertiseAddresses: []string{"[2001:db8::10]:9905"},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &PeerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+
+		// the external Service gets the families of the cluster too
+		for _, name := range []string{"peer", "peer" + ExternalServiceSuffix} {
+			svc := &v1.Service{}
+			Expect(getTestObject(ctx, cluster, name, svc)).To(Succeed())
+			Expect(*svc.Spec.IPFamilyPolicy).To(Equal(v1.IPFamilyPolicyPreferDualStack))
+		}
+
+		// a dual-stack pod listens on both families, and IPv6 addresses are advertised with brackets
+		dep := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "peer", dep)).To(Succeed())
+		env := dep.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(v1.EnvVar{Name: P2PListenAddressesSetting, Value: "0.0.0.0,::"}))
+		Expect(env).To(ContainElement(v1.EnvVar{Name: P2PAdvertiseAddressesSetting, Value: "[2001:db8::10]:9905"}))
+	})
+})
@@ -111,6 +111,7 @@ func defaultFaucetDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -119,6 +120,8 @@ func defaultFaucetDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           image,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "faucet",
+						SecurityContext: defaultContainerSecurityContext(),
+						VolumeMounts:    []corev1.VolumeMount{tmpVolumeMount()},
 						// Make sane defaults, and this should be configurable
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -145,7 +148,7 @@ func defaultFaucetDeploymentSpec() *appsv1.DeploymentSpec {
 						},
 					},
 				},
-				Volumes: []corev1.Volume{},
+				Volumes: []corev1.Volume{tmpVolume()},
 			},
 		},
 	}
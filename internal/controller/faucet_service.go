@@ -39,6 +39,7 @@ func (r *FaucetReconciler) updateService(svc *corev1.Service, faucet *teranodev1
 		return err
 	}
 	svc.Spec = *defaultFaucetServiceSpec()
+	utils.ApplyIPFamilies(svc, faucet.Spec.IPFamilies, faucet.Spec.IPFamilyPolicy)
 	return nil
 }
 
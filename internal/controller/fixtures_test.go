@@ -0,0 +1,127 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// testClusterName is the name of the Cluster of every namespace created by newTestCluster
+const testClusterName = "test-resource"
+
+// newTestCluster creates a Cluster with every service enabled, its spec changed by mutate. It's in a namespace of
+// its own, since envtest doesn't garbage collect what a spec leaves behind.
+func newTestCluster(ctx context.Context, mutate func(*teranodev1alpha1.Cluster)) *teranodev1alpha1.Cluster {
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "cluster-"},
+	}
+	Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	cluster := &teranodev1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: namespace.Name,
+		},
+		Spec: teranodev1alpha1.ClusterSpec{
+			Asset:            teranodev1alpha1.AssetConfig{Spec: &teranodev1alpha1.AssetSpec{}},
+			AlertSystem:      teranodev1alpha1.AlertSystemConfig{Spec: &teranodev1alpha1.AlertSystemSpec{}},
+			BlockAssembly:    teranodev1alpha1.BlockAssemblyConfig{Spec: &teranodev1alpha1.BlockAssemblySpec{}},
+			Blockchain:       teranodev1alpha1.BlockchainConfig{Spec: &teranodev1alpha1.BlockchainSpec{}},
+			BlockPersister:   teranodev1alpha1.BlockPersisterConfig{Spec: &teranodev1alpha1.BlockPersisterSpec{}},
+			BlockValidator:   teranodev1alpha1.BlockValidatorConfig{Spec: &teranodev1alpha1.BlockValidatorSpec{}},
+			Bootstrap:        teranodev1alpha1.BootstrapConfig{Spec: &teranodev1alpha1.BootstrapSpec{}},
+			Coinbase:         teranodev1alpha1.CoinbaseConfig{Spec: &teranodev1alpha1.CoinbaseSpec{}},
+			Legacy:           teranodev1alpha1.LegacyConfig{Spec: &teranodev1alpha1.LegacySpec{}},
+			Peer:             teranodev1alpha1.PeerConfig{Spec: &teranodev1alpha1.PeerSpec{}},
+			Propagation:      teranodev1alpha1.PropagationConfig{Spec: &teranodev1alpha1.PropagationSpec{}},
+			RPC:              teranodev1alpha1.RPCConfig{Spec: &teranodev1alpha1.RPCSpec{}},
+			SubtreeValidator: teranodev1alpha1.SubtreeValidatorConfig{Spec: &teranodev1alpha1.SubtreeValidatorSpec{}},
+			UtxoPersister:    teranodev1alpha1.UtxoPersisterConfig{Spec: &teranodev1alpha1.UtxoPersisterSpec{}},
+			Validator:        teranodev1alpha1.ValidatorConfig{Spec: &teranodev1alpha1.ValidatorSpec{}},
+			Pruner:           teranodev1alpha1.PrunerConfig{Spec: &teranodev1alpha1.PrunerSpec{}},
+		},
+	}
+	enableAllServices(cluster)
+	if mutate != nil {
+		mutate(cluster)
+	}
+	Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+	DeferCleanup(func() {
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, cluster))).To(Succeed())
+	})
+	return cluster
+}
+
+// updateTestObject gets the latest version of the object, changes it with mutate and updates it
+func updateTestObject(ctx context.Context, obj client.Object, mutate func()) error {
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return err
+	}
+	mutate()
+	return k8sClient.Update(ctx, obj)
+}
+
+// getTestObject gets an object of the namespace of the cluster
+func getTestObject(ctx context.Context, cluster *teranodev1alpha1.Cluster, name string, obj client.Object) error {
+	return k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, obj)
+}
+
+// reconcileTestCluster reconciles the cluster, which creates or updates the CRs of its services
+func reconcileTestCluster(ctx context.Context, cluster *teranodev1alpha1.Cluster) {
+	controllerReconciler := &ClusterReconciler{
+		Client: k8sClient,
+		Scheme: k8sClient.Scheme(),
+	}
+	_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+		NamespacedName: client.ObjectKeyFromObject(cluster),
+	})
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// testServiceName is the name of the CR the cluster creates for a service, such as blockvalidator
+func testServiceName(cluster *teranodev1alpha1.Cluster, service string) types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s", cluster.Name, service),
+		Namespace: cluster.Namespace,
+	}
+}
+
+// reconcileTestService reconciles the CR the cluster created for a service
+func reconcileTestService(ctx context.Context, r reconcile.Reconciler, cluster *teranodev1alpha1.Cluster, service string) (reconcile.Result, error) {
+	return r.Reconcile(ctx, reconcile.Request{
+		NamespacedName: testServiceName(cluster, service),
+	})
+}
+
+// fakeVolumeStats reports the same usage for every PVC, on a filesystem the size of its capacity
+type fakeVolumeStats struct {
+	used int64
+}
+
+func (f *fakeVolumeStats) VolumeUsage(ctx context.Context, pvc *v1.PersistentVolumeClaim) (int64, int64, error) {
+	return f.used, pvc.Status.Capacity.Storage().Value(), nil
+}
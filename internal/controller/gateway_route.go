@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// routeTarget is the service port an ingress definition routes to
+type routeTarget struct {
+	// Name of the Ingress or route
+	Name        string
+	App         string
+	ServiceName string
+	Port        int32
+	// RouteType is used when the ingress definition doesn't set one
+	RouteType teranodev1alpha1.RouteType
+	// Path defaults to /
+	Path     string
+	PathType networkingv1.PathType
+	// Debug ingresses are refused without allowedSourceRanges or basicAuthSecretRef
+	Debug bool
+	// Listed ingresses come from the deployment overrides, and are deleted once removed from them
+	Listed bool
+}
+
+// labels returns the labels of the Ingress or route
+func (t routeTarget) labels() map[string]string {
+	labels := getAppLabels(t.App)
+	if t.Listed {
+		labels[ServiceIngressLabel] = "true"
+	}
+	return labels
+}
+
+func (t routeTarget) path() string {
+	if t.Path == "" {
+		return "/"
+	}
+	return t.Path
+}
+
+func (t routeTarget) pathType() networkingv1.PathType {
+	if t.PathType == "" {
+		return networkingv1.PathTypePrefix
+	}
+	return t.PathType
+}
+
+// ingressGateway returns the gateway the ingress definition is routed through, or nil when it uses an Ingress
+func ingressGateway(c client.Client, owner serviceOwner, def *teranodev1alpha1.IngressDef) *teranodev1alpha1.GatewayRef {
+	if def == nil {
+		return nil
+	}
+	if def.Gateway != nil {
+		return def.Gateway
+	}
+	if clusterOwner := utils.GetClusterOwner(c, context.Background(), owner.Metadata()); clusterOwner != nil {
+		return clusterOwner.Spec.Gateway
+	}
+	return nil
+}
+
+// reconcileGatewayRoute creates the Gateway API route of an ingress definition that uses a gateway and removes
+// the Ingress it replaces. It returns false when the definition should be reconciled as an Ingress.
+func reconcileGatewayRoute(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner,
+	def *teranodev1alpha1.IngressDef, target routeTarget) (bool, error) {
+	gateway := ingressGateway(c, owner, def)
+	if gateway == nil {
+		return false, deleteGatewayRoutes(ctx, c, owner, target.Name, "")
+	}
+	cluster := endpointCluster(ctx, c, owner)
+	def = resolveIngressHost(cluster, def, target.Name)
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: owner.GetNamespace(),
+		},
+	}
+	if err := c.Delete(ctx, &ingress); client.IgnoreNotFound(err) != nil {
+		return true, err
+	}
+
+	objectMeta := metav1.ObjectMeta{
+		Name:      target.Name,
+		Namespace: owner.GetNamespace(),
+		Labels:    target.labels(),
+	}
+	parentRefs := []gatewayv1.ParentReference{gatewayParentRef(gateway)}
+	var hostnames []gatewayv1.Hostname
+	if def.Host != "" {
+		hostnames = []gatewayv1.Hostname{gatewayv1.Hostname(def.Host)}
+	}
+	backendRef := gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(target.ServiceName),
+			Port: ptr.To(target.Port),
+		},
+	}
+
+	routeType := def.RouteType
+	if routeType == "" {
+		routeType = target.RouteType
+	}
+	if err := deleteGatewayRoutes(ctx, c, owner, target.Name, routeType); err != nil {
+		return true, err
+	}
+	var route client.Object
+	var mutate func()
+	switch routeType {
+	case teranodev1alpha1.RouteTypeGRPC:
+		grpcRoute := &gatewayv1.GRPCRoute{ObjectMeta: objectMeta}
+		route = grpcRoute
+		mutate = func() {
+			grpcRoute.Spec.ParentRefs = parentRefs
+			grpcRoute.Spec.Hostnames = hostnames
+			grpcRoute.Spec.Rules = []gatewayv1.GRPCRouteRule{
+				{
+					BackendRefs: []gatewayv1.GRPCBackendRef{
+						{
+							BackendRef: backendRef,
+						},
+					},
+				},
+			}
+		}
+	case teranodev1alpha1.RouteTypeTLS:
+		tlsRoute := &gatewayv1alpha2.TLSRoute{ObjectMeta: objectMeta}
+		route = tlsRoute
+		mutate = func() {
+			tlsRoute.Spec.ParentRefs = parentRefs
+			tlsRoute.Spec.Hostnames = hostnames
+			tlsRoute.Spec.Rules = []gatewayv1alpha2.TLSRouteRule{
+				{
+					BackendRefs: []gatewayv1.BackendRef{backendRef},
+				},
+			}
+		}
+	default:
+		httpRoute := &gatewayv1.HTTPRoute{ObjectMeta: objectMeta}
+		route = httpRoute
+		pathMatch := gatewayv1.PathMatchPathPrefix
+		if target.pathType() == networkingv1.PathTypeExact {
+			pathMatch = gatewayv1.PathMatchExact
+		}
+		mutate = func() {
+			httpRoute.Spec.ParentRefs = parentRefs
+			httpRoute.Spec.Hostnames = hostnames
+			httpRoute.Spec.Rules = []gatewayv1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1.HTTPRouteMatch{
+						{
+							Path: &gatewayv1.HTTPPathMatch{
+								Type:  ptr.To(pathMatch),
+								Value: ptr.To(target.path()),
+							},
+						},
+					},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{
+							BackendRef: backendRef,
+						},
+					},
+				},
+			}
+		}
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, route, func() error {
+		if err := controllerutil.SetControllerReference(owner, route, scheme); err != nil {
+			return err
+		}
+		labels := route.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range target.labels() {
+			labels[k] = v
+		}
+		route.SetLabels(labels)
+		utils.ReplaceAnnotations(route, def.Annotations)
+		mutate()
+		setEndpointHostname(route, cluster, def.Host)
+		return nil
+	})
+	return true, err
+}
+
+// deleteGatewayRoutes deletes the routes of the owner with the given name, except for the one of the route type to keep
+func deleteGatewayRoutes(ctx context.Context, c client.Client, owner serviceOwner, name string,
+	keep teranodev1alpha1.RouteType) error {
+	if keep != "" && keep != teranodev1alpha1.RouteTypeGRPC && keep != teranodev1alpha1.RouteTypeTLS {
+		keep = teranodev1alpha1.RouteTypeHTTP
+	}
+	routes := map[teranodev1alpha1.RouteType]client.Object{
+		teranodev1alpha1.RouteTypeHTTP: &gatewayv1.HTTPRoute{},
+		teranodev1alpha1.RouteTypeGRPC: &gatewayv1.GRPCRoute{},
+		teranodev1alpha1.RouteTypeTLS:  &gatewayv1alpha2.TLSRoute{},
+	}
+	for routeType, route := range routes {
+		if routeType == keep {
+			continue
+		}
+		route.SetName(name)
+		route.SetNamespace(owner.GetNamespace())
+		// the Gateway API CRDs, or the experimental TLSRoute, may not be installed
+		if err := deleteOwned(ctx, c, owner, route); err != nil && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func gatewayParentRef(gateway *teranodev1alpha1.GatewayRef) gatewayv1.ParentReference {
+	ref := gatewayv1.ParentReference{
+		Name: gatewayv1.ObjectName(gateway.Name),
+	}
+	if gateway.Namespace != "" {
+		ref.Namespace = ptr.To(gatewayv1.Namespace(gateway.Namespace))
+	}
+	if gateway.SectionName != "" {
+		ref.SectionName = ptr.To(gatewayv1.SectionName(gateway.SectionName))
+	}
+	return ref
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Gateway API routes", func() {
+	ctx := context.Background()
+
+	It("should route ingresses through the Gateway API and delete the routes that no longer apply", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Gateway = &teranodev1alpha1.GatewayRef{
+				Name:      "teranode",
+				Namespace: "gateway-system",
+			}
+			cluster.Spec.Peer.Spec = &teranodev1alpha1.PeerSpec{
+				GrpcIngress: &teranodev1alpha1.IngressDef{
+					Host: "peer.example.com",
+				},
+				WsIngress: &teranodev1alpha1.IngressDef{
+					Host: "ws.example.com",
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		peerReconciler := &PeerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+
+		grpcRoute := &gatewayv1.GRPCRoute{}
+		Expect(getTestObject(ctx, cluster, "peer-grpc", grpcRoute)).To(Succeed())
+		Expect(grpcRoute.Spec.ParentRefs).To(HaveLen(1))
+		Expect(string(grpcRoute.Spec.ParentRefs[0].Name)).To(Equal("teranode"))
+		Expect(string(*grpcRoute.Spec.ParentRefs[0].Namespace)).To(Equal("gateway-system"))
+		Expect(grpcRoute.Spec.Hostnames).To(ConsistOf(gatewayv1.Hostname("peer.example.com")))
+		Expect(*grpcRoute.Spec.Rules[0].BackendRefs[0].Port).To(Equal(gatewayv1.PortNumber(PeerPort)))
+
+		httpRoute := &gatewayv1.HTTPRoute{}
+		Expect(getTestObject(ctx, cluster, "peer-ws", httpRoute)).To(Succeed())
+		Expect(string(httpRoute.Spec.Rules[0].BackendRefs[0].Name)).To(Equal("asset"))
+		err = getTestObject(ctx, cluster, "peer-grpc", &networkingv1.Ingress{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// a route of another type replaces the previous one
+		peer := &teranodev1alpha1.Peer{}
+		peer.Name, peer.Namespace = testServiceName(cluster, "peer").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, peer, func() {
+			peer.Spec.WsIngress.RouteType = teranodev1alpha1.RouteTypeTLS
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTestObject(ctx, cluster, "peer-ws", &gatewayv1alpha2.TLSRoute{})).To(Succeed())
+		err = getTestObject(ctx, cluster, "peer-ws", &gatewayv1.HTTPRoute{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// and an Ingress replaces the routes once the gateway is removed
+		Expect(updateTestObject(ctx, cluster, func() {
+			cluster.Spec.Gateway = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+		err = getTestObject(ctx, cluster, "peer-grpc", &gatewayv1.GRPCRoute{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		err = getTestObject(ctx, cluster, "peer-ws", &gatewayv1alpha2.TLSRoute{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(getTestObject(ctx, cluster, "peer-grpc", &networkingv1.Ingress{})).To(Succeed())
+	})
+})
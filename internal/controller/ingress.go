@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// ErrUnknownServicePort is returned when an ingress routes to a port the service doesn't have
+var ErrUnknownServicePort = errors.New("service has no port with this name")
+
+// ServiceIngressLabel is set on the Ingresses and routes listed in the deployment overrides of a service,
+// so that they are deleted once removed from the list
+const ServiceIngressLabel = "teranode.bsvblockchain.org/service-ingress"
+
+// reconcileIngress creates the Ingress of an ingress definition, or its Gateway API route when it uses
+// a gateway, along with its certificate. Every ingress of the operator is rendered here.
+func reconcileIngress(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner,
+	def *teranodev1alpha1.IngressDef, target routeTarget) error {
+	cluster := endpointCluster(ctx, c, owner)
+	def = resolveIngressHost(cluster, def, target.Name)
+	if err := validateIngressAccess(c, owner, def, target); err != nil {
+		return err
+	}
+	if err := reconcileCertificate(ctx, c, scheme, owner, def, target.Name, target.App); err != nil {
+		return err
+	}
+	if routed, err := reconcileGatewayRoute(ctx, c, scheme, owner, def, target); routed {
+		return err
+	}
+	middlewares, err := reconcileIngressMiddlewares(ctx, c, scheme, owner, def, target)
+	if err != nil {
+		return err
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: owner.GetNamespace(),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, c, ingress, func() error {
+		if err := controllerutil.SetControllerReference(owner, ingress, scheme); err != nil {
+			return err
+		}
+		if ingress.Labels == nil {
+			ingress.Labels = map[string]string{}
+		}
+		for k, v := range target.labels() {
+			ingress.Labels[k] = v
+		}
+		utils.ReplaceAnnotations(ingress, def.Annotations)
+		ingress.Spec.IngressClassName = def.ClassName
+		ingress.Spec.Rules = []networkingv1.IngressRule{
+			{
+				Host: def.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     target.path(),
+								PathType: ptr.To(target.pathType()),
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: target.ServiceName,
+										Port: networkingv1.ServiceBackendPort{
+											Number: target.Port,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		ingress.Spec.TLS = nil
+		setIngressTLS(ingress, def)
+		setIngressAccess(ingress, def, middlewares)
+		setEndpointHostname(ingress, cluster, def.Host)
+		return nil
+	})
+	return err
+}
+
+// reconcileServiceIngresses creates the ingresses listed in the deployment overrides of a service,
+// and deletes the ones removed from the list
+func reconcileServiceIngresses(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner,
+	app string, serviceName string) error {
+	var ingresses []teranodev1alpha1.ServiceIngress
+	if overrides := owner.DeploymentOverrides(); overrides != nil {
+		ingresses = overrides.Ingresses
+	}
+	svc := corev1.Service{}
+	if len(ingresses) > 0 {
+		if err := c.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: owner.GetNamespace()}, &svc); err != nil {
+			return err
+		}
+	}
+	names := map[string]bool{}
+	for i := range ingresses {
+		serviceIngress := ingresses[i]
+		var port *corev1.ServicePort
+		for j := range svc.Spec.Ports {
+			if svc.Spec.Ports[j].Name == serviceIngress.Port {
+				port = &svc.Spec.Ports[j]
+			}
+		}
+		if port == nil {
+			return fmt.Errorf("%w: %s/%s", ErrUnknownServicePort, serviceName, serviceIngress.Port)
+		}
+		target := routeTarget{
+			Name:        serviceIngress.Name,
+			App:         app,
+			ServiceName: serviceName,
+			Port:        port.Port,
+			RouteType:   teranodev1alpha1.RouteTypeHTTP,
+			Path:        serviceIngress.Path,
+			Debug:       debugPorts[serviceIngress.Port],
+			Listed:      true,
+		}
+		if target.Name == "" {
+			target.Name = serviceName + "-" + serviceIngress.Port
+		}
+		if serviceIngress.PathType != nil {
+			target.PathType = *serviceIngress.PathType
+		}
+		if strings.Contains(serviceIngress.Port, "grpc") {
+			target.RouteType = teranodev1alpha1.RouteTypeGRPC
+		}
+		if err := reconcileIngress(ctx, c, scheme, owner, &serviceIngress.IngressDef, target); err != nil {
+			return err
+		}
+		names[target.Name] = true
+	}
+	return deleteServiceIngresses(ctx, c, owner, app, names)
+}
+
+// deleteServiceIngresses deletes the listed ingresses of the owner that aren't in names, along with their
+// routes, certificate and middlewares
+func deleteServiceIngresses(ctx context.Context, c client.Client, owner serviceOwner, app string, names map[string]bool) error {
+	selector := client.MatchingLabels{
+		"app":               app,
+		ServiceIngressLabel: "true",
+	}
+	lists := []client.ObjectList{
+		&networkingv1.IngressList{},
+		&gatewayv1.HTTPRouteList{},
+		&gatewayv1.GRPCRouteList{},
+		&gatewayv1alpha2.TLSRouteList{},
+	}
+	stale := map[string]bool{}
+	for _, list := range lists {
+		if err := c.List(ctx, list, client.InNamespace(owner.GetNamespace()), selector); err != nil {
+			// the Gateway API CRDs may not be installed
+			if apimeta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		if err := apimeta.EachListItem(list, func(obj runtime.Object) error {
+			item, ok := obj.(client.Object)
+			if ok && metav1.IsControlledBy(item, owner) && !names[item.GetName()] {
+				stale[item.GetName()] = true
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	for name := range stale {
+		if err := deleteIngress(ctx, c, owner, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIngress deletes the Ingress or routes of an ingress definition, along with its certificate and middlewares
+func deleteIngress(ctx context.Context, c client.Client, owner serviceOwner, name string) error {
+	if err := deleteOwned(ctx, c, owner, &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()},
+	}); err != nil {
+		return err
+	}
+	if err := deleteGatewayRoutes(ctx, c, owner, name, ""); err != nil {
+		return err
+	}
+	if err := deleteCertificate(ctx, c, owner, name); err != nil {
+		return err
+	}
+	for _, suffix := range []string{"-allowlist", "-basic-auth"} {
+		middleware := &unstructured.Unstructured{}
+		middleware.SetGroupVersionKind(MiddlewareGVK)
+		middleware.SetName(name + suffix)
+		middleware.SetNamespace(owner.GetNamespace())
+		if err := deleteOwned(ctx, c, owner, middleware); err != nil && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// Ingress annotations restricting access for the nginx and traefik ingress controllers
+const (
+	NginxSourceRangeAnnotation  = "nginx.ingress.kubernetes.io/whitelist-source-range"
+	NginxAuthTypeAnnotation     = "nginx.ingress.kubernetes.io/auth-type"
+	NginxAuthSecretAnnotation   = "nginx.ingress.kubernetes.io/auth-secret"
+	NginxAuthRealmAnnotation    = "nginx.ingress.kubernetes.io/auth-realm"
+	TraefikMiddlewareAnnotation = "traefik.ingress.kubernetes.io/router.middlewares"
+)
+
+var (
+	// ErrUnrestrictedIngress is returned for debug and profiler ingresses that anyone could reach
+	ErrUnrestrictedIngress = errors.New("debug and profiler ingresses need allowedSourceRanges or basicAuthSecretRef")
+	// ErrGatewayIngressAccess is returned for restricted ingresses routed through a gateway, which can't enforce the restriction
+	ErrGatewayIngressAccess = errors.New("allowedSourceRanges and basicAuthSecretRef aren't supported on Gateway API routes")
+	// ErrIngressClassAccess is returned for restricted ingresses of a class other than nginx or traefik, whose
+	// controller would ignore the restriction
+	ErrIngressClassAccess = errors.New("allowedSourceRanges and basicAuthSecretRef are only supported on nginx and traefik ingress classes")
+)
+
+// MiddlewareGVK is the traefik Middleware kind. traefik is optional, so middlewares are handled as unstructured objects.
+var MiddlewareGVK = schema.GroupVersionKind{
+	Group:   "traefik.io",
+	Version: "v1alpha1",
+	Kind:    "Middleware",
+}
+
+// debugPorts are the service ports that must not be exposed without a restriction
+var debugPorts = map[string]bool{
+	"delve":    true,
+	"profiler": true,
+}
+
+// ingressRestricted returns whether access to the ingress is restricted
+func ingressRestricted(def *teranodev1alpha1.IngressDef) bool {
+	return len(def.AllowedSourceRanges) > 0 || def.BasicAuthSecretRef != nil
+}
+
+func isTraefikIngress(def *teranodev1alpha1.IngressDef) bool {
+	return def.ClassName != nil && strings.Contains(*def.ClassName, "traefik")
+}
+
+// isNginxIngress is true for nginx classes, and for the default class, which is ingress-nginx unless the
+// cluster lists other ingress controllers
+func isNginxIngress(def *teranodev1alpha1.IngressDef) bool {
+	return def.ClassName == nil || strings.Contains(*def.ClassName, "nginx")
+}
+
+// validateIngressAccess refuses ingresses whose restriction can't be applied, and unrestricted debug ingresses
+func validateIngressAccess(c client.Client, owner serviceOwner, def *teranodev1alpha1.IngressDef, target routeTarget) error {
+	if target.Debug && !ingressRestricted(def) {
+		return fmt.Errorf("%w: %s", ErrUnrestrictedIngress, target.Name)
+	}
+	if !ingressRestricted(def) {
+		return nil
+	}
+	if ingressGateway(c, owner, def) != nil {
+		return fmt.Errorf("%w: %s", ErrGatewayIngressAccess, target.Name)
+	}
+	if !isTraefikIngress(def) && !isNginxIngress(def) {
+		return fmt.Errorf("%w: %s", ErrIngressClassAccess, target.Name)
+	}
+	return nil
+}
+
+// reconcileIngressMiddlewares creates the traefik middlewares restricting access to the ingress,
+// and returns their references for the router.middlewares annotation
+func reconcileIngressMiddlewares(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner,
+	def *teranodev1alpha1.IngressDef, target routeTarget) ([]string, error) {
+	if !isTraefikIngress(def) {
+		return nil, nil
+	}
+	middlewares := map[string]map[string]interface{}{}
+	if len(def.AllowedSourceRanges) > 0 {
+		sourceRange := make([]interface{}, 0, len(def.AllowedSourceRanges))
+		for _, cidr := range def.AllowedSourceRanges {
+			sourceRange = append(sourceRange, cidr)
+		}
+		middlewares[target.Name+"-allowlist"] = map[string]interface{}{
+			"ipAllowList": map[string]interface{}{
+				"sourceRange": sourceRange,
+			},
+		}
+	}
+	if def.BasicAuthSecretRef != nil {
+		middlewares[target.Name+"-basic-auth"] = map[string]interface{}{
+			"basicAuth": map[string]interface{}{
+				"secret": def.BasicAuthSecretRef.Name,
+			},
+		}
+	}
+
+	refs := []string{}
+	for _, name := range []string{target.Name + "-allowlist", target.Name + "-basic-auth"} {
+		middleware := &unstructured.Unstructured{}
+		middleware.SetGroupVersionKind(MiddlewareGVK)
+		middleware.SetName(name)
+		middleware.SetNamespace(owner.GetNamespace())
+		spec, ok := middlewares[name]
+		if !ok {
+			if err := c.Delete(ctx, middleware); client.IgnoreNotFound(err) != nil && !apimeta.IsNoMatchError(err) {
+				return nil, err
+			}
+			continue
+		}
+		_, err := controllerutil.CreateOrUpdate(ctx, c, middleware, func() error {
+			if err := controllerutil.SetControllerReference(owner, middleware, scheme); err != nil {
+				return err
+			}
+			middleware.SetLabels(getAppLabels(target.App))
+			return unstructured.SetNestedMap(middleware.Object, spec, "spec")
+		})
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, fmt.Sprintf("%s-%s@kubernetescrd", owner.GetNamespace(), name))
+	}
+	return refs, nil
+}
+
+// setIngressAccess annotates the ingress with the restrictions of the ingress definition
+func setIngressAccess(ingress *networkingv1.Ingress, def *teranodev1alpha1.IngressDef, middlewares []string) {
+	if ingress.Annotations == nil {
+		ingress.Annotations = map[string]string{}
+	}
+	// drop restrictions that were removed from the definition
+	for _, annotation := range []string{
+		NginxSourceRangeAnnotation,
+		NginxAuthTypeAnnotation,
+		NginxAuthSecretAnnotation,
+		NginxAuthRealmAnnotation,
+		TraefikMiddlewareAnnotation,
+	} {
+		if _, ok := def.Annotations[annotation]; !ok {
+			delete(ingress.Annotations, annotation)
+		}
+	}
+
+	if isTraefikIngress(def) {
+		if len(middlewares) > 0 {
+			ingress.Annotations[TraefikMiddlewareAnnotation] = strings.Join(middlewares, ",")
+		}
+		return
+	}
+	if !isNginxIngress(def) {
+		return
+	}
+	if len(def.AllowedSourceRanges) > 0 {
+		ingress.Annotations[NginxSourceRangeAnnotation] = strings.Join(def.AllowedSourceRanges, ",")
+	}
+	if def.BasicAuthSecretRef != nil {
+		ingress.Annotations[NginxAuthTypeAnnotation] = "basic"
+		ingress.Annotations[NginxAuthSecretAnnotation] = def.BasicAuthSecretRef.Name
+		ingress.Annotations[NginxAuthRealmAnnotation] = "Authentication Required"
+	}
+}
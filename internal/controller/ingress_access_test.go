@@ -0,0 +1,107 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Ingress access", func() {
+	ctx := context.Background()
+
+	It("should restrict access to debug ingresses", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Propagation.Spec = &teranodev1alpha1.PropagationSpec{
+				DelveIngress: &teranodev1alpha1.IngressDef{
+					Host:                "delve.example.com",
+					AllowedSourceRanges: []string{"10.0.0.0/8", "192.168.0.0/16"},
+				},
+				ProfilerIngress: &teranodev1alpha1.IngressDef{
+					Host: "profiler.example.com",
+					BasicAuthSecretRef: &v1.LocalObjectReference{
+						Name: "profiler-users",
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		propagationReconciler := &PropagationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(getTestObject(ctx, cluster, "propagation-delve", ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(NginxSourceRangeAnnotation, "10.0.0.0/8,192.168.0.0/16"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number).To(Equal(int32(DebuggerPort)))
+		Expect(getTestObject(ctx, cluster, "propagation-profiler", ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(NginxAuthTypeAnnotation, "basic"))
+		Expect(ingress.Annotations).To(HaveKeyWithValue(NginxAuthSecretAnnotation, "profiler-users"))
+
+		// an unrestricted profiler ingress is refused
+		propagation := &teranodev1alpha1.Propagation{}
+		propagation.Name, propagation.Namespace = testServiceName(cluster, "propagation").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, propagation, func() {
+			propagation.Spec.ProfilerIngress.BasicAuthSecretRef = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "propagation"), propagation)).To(Succeed())
+		condition := apimeta.FindStatusCondition(propagation.Status.Conditions, teranodev1alpha1.ConditionReconciled)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring(ErrUnrestrictedIngress.Error()))
+
+		// the profiler ingress is deleted once removed
+		Expect(updateTestObject(ctx, propagation, func() {
+			propagation.Spec.ProfilerIngress = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		err = getTestObject(ctx, cluster, "propagation-profiler", ingress)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// a restriction the ingress controller would ignore is refused
+		Expect(updateTestObject(ctx, propagation, func() {
+			propagation.Spec.DelveIngress.ClassName = ptr.To("haproxy")
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "propagation"), propagation)).To(Succeed())
+		condition = apimeta.FindStatusCondition(propagation.Status.Conditions, teranodev1alpha1.ConditionReconciled)
+		Expect(condition.Message).To(ContainSubstring(ErrIngressClassAccess.Error()))
+
+		// and so is the delve ingress once removed
+		Expect(updateTestObject(ctx, propagation, func() {
+			propagation.Spec.DelveIngress = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		err = getTestObject(ctx, cluster, "propagation-delve", ingress)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Ingresses", func() {
+	ctx := context.Background()
+
+	It("should expose service ports through the ingresses list", func() {
+		exact := networkingv1.PathTypeExact
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Blockchain.Spec = &teranodev1alpha1.BlockchainSpec{
+				DeploymentOverrides: &teranodev1alpha1.DeploymentOverrides{
+					Ingresses: []teranodev1alpha1.ServiceIngress{
+						{
+							Port:     "http",
+							Path:     "/api/v1",
+							PathType: &exact,
+							IngressDef: teranodev1alpha1.IngressDef{
+								Host:      "blockchain.example.com",
+								ClassName: ptr.To("nginx"),
+								Annotations: map[string]string{
+									"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+								},
+							},
+						},
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		blockchainReconciler := &BlockchainReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, blockchainReconciler, cluster, "blockchain")
+		Expect(err).NotTo(HaveOccurred())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(getTestObject(ctx, cluster, BlockchainServiceName+"-http", ingress)).To(Succeed())
+		Expect(ingress.Spec.IngressClassName).To(Equal(ptr.To("nginx")))
+		Expect(ingress.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-redirect", "false"))
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("blockchain.example.com"))
+		path := ingress.Spec.Rules[0].HTTP.Paths[0]
+		Expect(path.Path).To(Equal("/api/v1"))
+		Expect(path.PathType).To(Equal(&exact))
+		Expect(path.Backend.Service.Name).To(Equal(BlockchainServiceName))
+
+		// an entry removed from the list is deleted
+		blockchain := &teranodev1alpha1.Blockchain{}
+		blockchain.Name, blockchain.Namespace = testServiceName(cluster, "blockchain").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, blockchain, func() {
+			blockchain.Spec.DeploymentOverrides.Ingresses = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, blockchainReconciler, cluster, "blockchain")
+		Expect(err).NotTo(HaveOccurred())
+		err = getTestObject(ctx, cluster, BlockchainServiceName+"-http", &networkingv1.Ingress{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should replace the annotations of the fixed ingresses", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Peer.Spec = &teranodev1alpha1.PeerSpec{
+				WsIngress: &teranodev1alpha1.IngressDef{
+					Host: "peer-ws.example.com",
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600",
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		peerReconciler := &PeerReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(getTestObject(ctx, cluster, "peer-ws", ingress)).To(Succeed())
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("peer-ws.example.com"))
+		Expect(ingress.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-read-timeout", "3600"))
+
+		// an annotation removed from the spec is removed from the ingress
+		peer := &teranodev1alpha1.Peer{}
+		peer.Name, peer.Namespace = testServiceName(cluster, "peer").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, peer, func() {
+			peer.Spec.WsIngress.Annotations = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, peerReconciler, cluster, "peer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTestObject(ctx, cluster, "peer-ws", ingress)).To(Succeed())
+		Expect(ingress.Annotations).NotTo(HaveKey("nginx.ingress.kubernetes.io/proxy-read-timeout"))
+	})
+})
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// CertificateRequeueInterval is how often certificates that aren't ready yet are checked again
+const CertificateRequeueInterval = 30 * time.Second
+
+// CertificateGVK is the cert-manager Certificate kind. cert-manager is optional, so certificates are
+// handled as unstructured objects and aren't watched.
+var CertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// ErrCertificateHost is returned when an ingress with a certificate issuer has no host, and the cluster no domain to derive one from
+var ErrCertificateHost = errors.New("a certificate issuer needs a host or a cluster domain")
+
+// ingressTLSSecretName returns the secret holding the certificate of an ingress
+func ingressTLSSecretName(def *teranodev1alpha1.IngressDef, name string) string {
+	if def.TLS != nil && def.TLS.SecretName != "" {
+		return def.TLS.SecretName
+	}
+	return name + "-tls"
+}
+
+// setIngressTLS terminates TLS for the host of the ingress definition
+func setIngressTLS(ingress *networkingv1.Ingress, def *teranodev1alpha1.IngressDef) {
+	if def == nil || def.TLS == nil {
+		return
+	}
+	tls := networkingv1.IngressTLS{
+		SecretName: ingressTLSSecretName(def, ingress.Name),
+	}
+	if def.Host != "" {
+		tls.Hosts = []string{def.Host}
+	}
+	ingress.Spec.TLS = []networkingv1.IngressTLS{tls}
+}
+
+// reconcileCertificate creates the cert-manager Certificate of an ingress definition that has an issuer,
+// or deletes it when the definition no longer has one
+func reconcileCertificate(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner,
+	def *teranodev1alpha1.IngressDef, name string, app string) error {
+	if def == nil || def.TLS == nil || def.TLS.Issuer == nil {
+		return deleteCertificate(ctx, c, owner, name)
+	}
+	if def.Host == "" {
+		return fmt.Errorf("%w: %s", ErrCertificateHost, name)
+	}
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGVK)
+	cert.SetName(name)
+	cert.SetNamespace(owner.GetNamespace())
+	_, err := controllerutil.CreateOrUpdate(ctx, c, cert, func() error {
+		if err := controllerutil.SetControllerReference(owner, cert, scheme); err != nil {
+			return err
+		}
+		labels := cert.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range getAppLabels(app) {
+			labels[k] = v
+		}
+		cert.SetLabels(labels)
+		kind := def.TLS.Issuer.Kind
+		if kind == "" {
+			kind = "Issuer"
+		}
+		spec := map[string]interface{}{
+			"secretName": ingressTLSSecretName(def, name),
+			"dnsNames":   []interface{}{def.Host},
+			"issuerRef": map[string]interface{}{
+				"name":  def.TLS.Issuer.Name,
+				"kind":  kind,
+				"group": CertificateGVK.Group,
+			},
+		}
+		return unstructured.SetNestedMap(cert.Object, spec, "spec")
+	})
+	return err
+}
+
+// deleteCertificate deletes the cert-manager Certificate of an ingress definition, if the owner has one
+func deleteCertificate(ctx context.Context, c client.Client, owner serviceOwner, name string) error {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGVK)
+	cert.SetName(name)
+	cert.SetNamespace(owner.GetNamespace())
+	// cert-manager may not be installed
+	if err := deleteOwned(ctx, c, owner, cert); err != nil && !apimeta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+// certificateStatuses returns the readiness and expiry of the cert-manager certificates owned by the resource
+func certificateStatuses(ctx context.Context, c client.Client, owner serviceOwner) ([]teranodev1alpha1.CertificateStatus, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(CertificateGVK.GroupVersion().WithKind(CertificateGVK.Kind + "List"))
+	if err := c.List(ctx, list, client.InNamespace(owner.GetNamespace())); err != nil {
+		// cert-manager isn't installed
+		if apimeta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	statuses := []teranodev1alpha1.CertificateStatus{}
+	for _, cert := range list.Items {
+		if !metav1.IsControlledBy(&cert, owner) {
+			continue
+		}
+		status := teranodev1alpha1.CertificateStatus{
+			Name: cert.GetName(),
+		}
+		conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+		for _, condition := range conditions {
+			condition, ok := condition.(map[string]interface{})
+			if !ok || condition["type"] != "Ready" {
+				continue
+			}
+			status.Ready = condition["status"] == string(metav1.ConditionTrue)
+			status.Message, _ = condition["message"].(string)
+		}
+		if notAfter, found, _ := unstructured.NestedString(cert.Object, "status", "notAfter"); found {
+			if t, err := time.Parse(time.RFC3339, notAfter); err == nil {
+				status.NotAfter = &metav1.Time{Time: t}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// certificatesReady returns whether every certificate is ready
+func certificatesReady(statuses []teranodev1alpha1.CertificateStatus) bool {
+	for _, status := range statuses {
+		if !status.Ready {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Ingress TLS", func() {
+	ctx := context.Background()
+
+	It("should terminate TLS on ingresses", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Propagation.Spec = &teranodev1alpha1.PropagationSpec{
+				GrpcIngress: &teranodev1alpha1.IngressDef{
+					Host: "propagation.example.com",
+					TLS:  &teranodev1alpha1.IngressTLS{},
+				},
+			}
+			cluster.Spec.Coinbase.Spec = &teranodev1alpha1.CoinbaseSpec{
+				GrpcIngress: &teranodev1alpha1.IngressDef{
+					Host: "coinbase.example.com",
+					TLS: &teranodev1alpha1.IngressTLS{
+						SecretName: "wildcard-tls",
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		propagationReconciler := &PropagationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = reconcileTestService(ctx, &CoinbaseReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "coinbase")
+		Expect(err).NotTo(HaveOccurred())
+
+		// the secret defaults to one per ingress
+		ingress := &networkingv1.Ingress{}
+		Expect(getTestObject(ctx, cluster, "propagation-grpc", ingress)).To(Succeed())
+		Expect(ingress.Spec.TLS).To(ConsistOf(networkingv1.IngressTLS{
+			Hosts:      []string{"propagation.example.com"},
+			SecretName: "propagation-grpc-tls",
+		}))
+		Expect(getTestObject(ctx, cluster, "coinbase-grpc", ingress)).To(Succeed())
+		Expect(ingress.Spec.TLS).To(ConsistOf(networkingv1.IngressTLS{
+			Hosts:      []string{"coinbase.example.com"},
+			SecretName: "wildcard-tls",
+		}))
+
+		// a certificate can't be issued without a host
+		propagation := &teranodev1alpha1.Propagation{}
+		propagation.Name, propagation.Namespace = testServiceName(cluster, "propagation").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, propagation, func() {
+			propagation.Spec.GrpcIngress.Host = ""
+			propagation.Spec.GrpcIngress.TLS.Issuer = &teranodev1alpha1.IssuerRef{Name: "letsencrypt"}
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, propagationReconciler, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, testServiceName(cluster, "propagation"), propagation)).To(Succeed())
+		condition := apimeta.FindStatusCondition(propagation.Status.Conditions, teranodev1alpha1.ConditionReconciled)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring(ErrCertificateHost.Error()))
+	})
+})
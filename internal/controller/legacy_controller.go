@@ -22,6 +22,8 @@ import (
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -72,6 +74,8 @@ func (r *LegacyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	_, err := utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
+		r.ReconcileExposure,
 	)
 
 	if err != nil {
@@ -86,6 +90,10 @@ func (r *LegacyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		_ = r.Client.Status().Update(ctx, &legacy)
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, err
 	} else {
+		legacy.Status.ExternalAddresses, err = exposureAddresses(ctx, r.Client, req.Namespace, legacy.Spec.Exposure, "legacy", legacyExposurePorts())
+		if err != nil {
+			r.Log.Error(err, "unable to resolve external addresses")
+		}
 		apimeta.SetStatusCondition(&legacy.Status.Conditions,
 			metav1.Condition{
 				Type:    teranodev1alpha1.ConditionReconciled,
@@ -108,5 +116,8 @@ func (r *LegacyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&teranodev1alpha1.Legacy{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&networkingv1.Ingress{}).
 		Complete(r)
 }
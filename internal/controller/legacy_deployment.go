@@ -6,7 +6,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -20,20 +19,9 @@ func (r *LegacyReconciler) ReconcileDeployment(log logr.Logger) (bool, error) {
 	if err := r.Get(r.Context, r.NamespacedName, &legacy); err != nil {
 		return false, err
 	}
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "legacy",
-			Namespace: r.NamespacedName.Namespace,
-			Labels:    getAppLabels("legacy"),
-		},
-	}
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &dep, func() error {
-		return r.updateDeployment(&dep, &legacy)
+	return reconcileWorkload(r.Context, r.Client, r.Scheme, &legacy, legacyWorkload(&legacy), func(dep *appsv1.Deployment) error {
+		return r.updateDeployment(dep, &legacy)
 	})
-	if err != nil {
-		return false, err
-	}
-	return true, nil
 }
 
 func (r *LegacyReconciler) updateDeployment(dep *appsv1.Deployment, legacy *teranodev1alpha1.Legacy) error {
@@ -44,6 +32,7 @@ func (r *LegacyReconciler) updateDeployment(dep *appsv1.Deployment, legacy *tera
 	dep.Spec = *defaultLegacyDeploymentSpec()
 	utils.SetDeploymentOverrides(r.Client, dep, legacy)
 	utils.SetClusterOverrides(r.Client, dep, legacy)
+	setHostExposure(dep, legacy.Spec.Exposure, legacyExposurePorts())
 
 	return nil
 }
@@ -70,6 +59,7 @@ func defaultLegacyDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -78,6 +68,7 @@ func defaultLegacyDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            "legacy",
+						SecurityContext: defaultContainerSecurityContext(),
 						// Make sane defaults, and this should be configurable
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
@@ -88,40 +79,9 @@ func defaultLegacyDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("8Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						StartupProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							FailureThreshold: 30,
-							PeriodSeconds:    10,
-						},
+						ReadinessProbe: defaultReadinessProbe(HealthPort),
+						LivenessProbe:  defaultLivenessProbe(HealthPort),
+						StartupProbe:   defaultStartupProbe(HealthPort, LegacyStartupWindow),
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: DebuggerPort,
@@ -135,6 +95,10 @@ func defaultLegacyDeploymentSpec() *appsv1.DeploymentSpec {
 								ContainerPort: LegacyHTTPPort,
 								Protocol:      corev1.ProtocolTCP,
 							},
+							{
+								ContainerPort: LegacyP2PPort,
+								Protocol:      corev1.ProtocolTCP,
+							},
 							{
 								ContainerPort: HealthPort,
 								Protocol:      corev1.ProtocolTCP,
@@ -145,6 +109,7 @@ func defaultLegacyDeploymentSpec() *appsv1.DeploymentSpec {
 								MountPath: "/data",
 								Name:      SharedPVCName,
 							},
+							tmpVolumeMount(),
 						},
 					},
 				},
@@ -157,8 +122,21 @@ func defaultLegacyDeploymentSpec() *appsv1.DeploymentSpec {
 							},
 						},
 					},
+					tmpVolume(),
 				},
 			},
 		},
 	}
 }
+
+// legacyWorkload is the Deployment or StatefulSet of the legacy service
+func legacyWorkload(legacy *teranodev1alpha1.Legacy) workload {
+	return workload{
+		App:              "legacy",
+		Kind:             legacy.Spec.WorkloadKind,
+		DataPath:         LegacyDataPath,
+		StorageClass:     legacy.Spec.StorageClass,
+		StorageResources: legacy.Spec.StorageResources,
+		DataSource:       legacy.Spec.StorageDataSource,
+	}
+}
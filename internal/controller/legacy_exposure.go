@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// ReconcileExposure is the reconciler for the service exposing the legacy P2P ports outside the cluster
+func (r *LegacyReconciler) ReconcileExposure(log logr.Logger) (bool, error) {
+	legacy := teranodev1alpha1.Legacy{}
+	if err := r.Get(r.Context, r.NamespacedName, &legacy); err != nil {
+		return false, err
+	}
+	err := reconcileExposureService(r.Context, r.Client, r.Scheme, &legacy, legacy.Spec.Exposure, "legacy", legacyExposurePorts())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func legacyExposurePorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name:       "p2p",
+			Port:       int32(LegacyP2PPort),
+			TargetPort: intstr.FromInt32(LegacyP2PPort),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+}
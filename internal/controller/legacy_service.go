@@ -39,6 +39,7 @@ func (r *LegacyReconciler) updateService(svc *corev1.Service, legacy *teranodev1
 		return err
 	}
 	svc.Spec = *defaultLegacyServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, legacy)
 	return nil
 }
 
@@ -76,3 +77,15 @@ func defaultLegacyServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *LegacyReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	legacy := teranodev1alpha1.Legacy{}
+	if err := r.Get(r.Context, r.NamespacedName, &legacy); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &legacy, "legacy", "legacy"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -74,6 +74,8 @@ func (r *PeerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		// r.Validate,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
+		r.ReconcileExposure,
 		r.ReconcileGrpcIngress,
 		r.ReconcileWsIngress,
 		r.ReconcileWssIngress,
@@ -94,6 +96,10 @@ func (r *PeerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		r.Log.Error(err, "requeuing object for reconciliation")
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, err
 	} else {
+		peer.Status.ExternalAddresses, err = exposureAddresses(ctx, r.Client, req.Namespace, peer.Spec.Exposure, "peer", peerExposurePorts())
+		if err != nil {
+			r.Log.Error(err, "unable to resolve external addresses")
+		}
 		apimeta.SetStatusCondition(&peer.Status.Conditions,
 			metav1.Condition{
 				Type:    teranodev1alpha1.ConditionReconciled,
@@ -104,7 +110,17 @@ func (r *PeerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		)
 	}
 
+	certificates, certErr := certificateStatuses(ctx, r.Client, &peer)
+	if certErr != nil {
+		r.Log.Error(certErr, "unable to fetch certificates")
+	}
+	peer.Status.Certificates = certificates
+
 	err = r.Client.Status().Update(ctx, &peer)
+	if !certificatesReady(peer.Status.Certificates) {
+		return ctrl.Result{RequeueAfter: CertificateRequeueInterval}, err
+	}
+
 	return ctrl.Result{Requeue: false, RequeueAfter: 0}, err
 }
 
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+	"github.com/bsv-blockchain/teranode-operator/internal/utils"
+)
+
+// ReconcileExposure is the reconciler for the service exposing the peer P2P ports outside the cluster
+func (r *PeerReconciler) ReconcileExposure(log logr.Logger) (bool, error) {
+	peer := teranodev1alpha1.Peer{}
+	if err := r.Get(r.Context, r.NamespacedName, &peer); err != nil {
+		return false, err
+	}
+	err := reconcileExposureService(r.Context, r.Client, r.Scheme, &peer, peer.Spec.Exposure, "peer", peerExposurePorts())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func peerExposurePorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name:       "p2p",
+			Port:       int32(PeerPort),
+			TargetPort: intstr.FromInt32(PeerPort),
+			Protocol:   corev1.ProtocolTCP,
+		},
+		{
+			Name:       "legacy",
+			Port:       int32(PeerLegacyPort),
+			TargetPort: intstr.FromInt32(PeerLegacyPort),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+}
+
+// setP2PAddresses makes the peer listen on the IP families of its service and advertise its external address
+func (r *PeerReconciler) setP2PAddresses(dep *appsv1.Deployment, peer *teranodev1alpha1.Peer) {
+	families, policy := utils.GetServiceIPFamilies(r.Client, peer)
+	setP2PListenAddresses(&dep.Spec.Template.Spec, families, policy, P2PListenAddressesSetting)
+	if peer.Spec.Exposure == nil || len(dep.Spec.Template.Spec.Containers) == 0 {
+		return
+	}
+	advertise := peer.Spec.Exposure.AdvertiseAddresses
+	// node addresses change whenever pods move, so only load balancer addresses are advertised automatically
+	if len(advertise) == 0 && exposureType(peer.Spec.Exposure) == teranodev1alpha1.ExposureTypeLoadBalancer {
+		addresses, err := exposureAddresses(r.Context, r.Client, peer.Namespace, peer.Spec.Exposure, "peer", peerExposurePorts()[:1])
+		if err != nil {
+			r.Log.Error(err, "unable to resolve the peer advertise addresses")
+		}
+		advertise = addresses
+	}
+	if len(advertise) > 0 {
+		setEnvDefault(&dep.Spec.Template.Spec.Containers[0], P2PAdvertiseAddressesSetting, strings.Join(advertise, ","))
+	}
+}
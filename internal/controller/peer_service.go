@@ -39,6 +39,7 @@ func (r *PeerReconciler) updateService(svc *corev1.Service, peer *teranodev1alph
 		return err
 	}
 	svc.Spec = *defaultPeerServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, peer)
 	return nil
 }
 
@@ -94,3 +95,15 @@ func defaultPeerServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *PeerReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	peer := teranodev1alpha1.Peer{}
+	if err := r.Get(r.Context, r.NamespacedName, &peer); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &peer, "peer", "peer"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
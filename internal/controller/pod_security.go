@@ -0,0 +1,59 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// TmpVolumeName is the writable emptyDir volume mounted at /tmp, since the root filesystem is read-only
+const TmpVolumeName = "tmp"
+
+// DefaultRunAsUser is the non-root user and group the services run as
+const DefaultRunAsUser = 1000
+
+// defaultPodSecurityContext follows the restricted Pod Security Standard. Data written by earlier, root
+// services is only handed over to the fsGroup on volumes that support it, see docs/cluster.md.
+func defaultPodSecurityContext() *corev1.PodSecurityContext {
+	onRootMismatch := corev1.FSGroupChangeOnRootMismatch
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot:        ptr.To(true),
+		RunAsUser:           ptr.To(int64(DefaultRunAsUser)),
+		RunAsGroup:          ptr.To(int64(DefaultRunAsUser)),
+		FSGroup:             ptr.To(int64(DefaultRunAsUser)),
+		FSGroupChangePolicy: &onRootMismatch,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// defaultContainerSecurityContext follows the restricted Pod Security Standard with a read-only root filesystem
+func defaultContainerSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		RunAsNonRoot:             ptr.To(true),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+func tmpVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: TmpVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func tmpVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		MountPath: "/tmp",
+		Name:      TmpVolumeName,
+	}
+}
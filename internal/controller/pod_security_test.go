@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Pod security", func() {
+	ctx := context.Background()
+
+	It("should harden the pods by default and keep the overrides", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.BlockValidator.Spec.DeploymentOverrides = &teranodev1alpha1.DeploymentOverrides{
+				SecurityContext: &v1.SecurityContext{
+					ReadOnlyRootFilesystem: ptr.To(false),
+				},
+				AutomountServiceAccountToken: ptr.To(false),
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &BlockchainReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "blockchain")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = reconcileTestService(ctx, &BlockValidatorReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "blockvalidator")
+		Expect(err).NotTo(HaveOccurred())
+
+		dep := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, BlockchainDeploymentName, dep)).To(Succeed())
+		podSpec := dep.Spec.Template.Spec
+		// the token stays mounted for the k8s:/// resolver the services discover each other with
+		Expect(podSpec.AutomountServiceAccountToken).To(BeNil())
+		Expect(podSpec.ServiceAccountName).To(Equal(DefaultServiceAccountName))
+		Expect(podSpec.SecurityContext.RunAsNonRoot).To(Equal(ptr.To(true)))
+		Expect(podSpec.SecurityContext.SeccompProfile.Type).To(Equal(v1.SeccompProfileTypeRuntimeDefault))
+		Expect(podSpec.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(true)))
+		Expect(podSpec.Containers[0].SecurityContext.Capabilities.Drop).To(ContainElement(v1.Capability("ALL")))
+		// the read-only root filesystem still has a writable /tmp
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(tmpVolumeMount()))
+		Expect(podSpec.Volumes).To(ContainElement(tmpVolume()))
+
+		// the override replaces the default
+		Expect(getTestObject(ctx, cluster, "block-validator", dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(false)))
+		Expect(dep.Spec.Template.Spec.AutomountServiceAccountToken).To(Equal(ptr.To(false)))
+	})
+})
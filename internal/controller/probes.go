@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// startupProbePeriodSeconds is the interval of the startup probe, the startup window is divided by it
+const startupProbePeriodSeconds = 10
+
+func defaultReadinessProbe(port int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/health/readiness",
+				Port: intstr.FromInt32(port),
+			},
+		},
+		InitialDelaySeconds: 1,
+		PeriodSeconds:       5,
+		FailureThreshold:    2,
+		TimeoutSeconds:      3,
+	}
+}
+
+// defaultLivenessProbe restarts a service whose liveness endpoint stops answering for a minute. A service catching
+// up is busy but alive, so single slow answers, such as under the load of a sync, don't restart it.
+func defaultLivenessProbe(port int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/health/liveness",
+				Port: intstr.FromInt32(port),
+			},
+		},
+		InitialDelaySeconds: 1,
+		PeriodSeconds:       10,
+		FailureThreshold:    6,
+		TimeoutSeconds:      5,
+	}
+}
+
+// defaultStartupProbe holds off the liveness and readiness probes until the service is alive or the window has passed.
+// It checks liveness, since a started service may stay unready for a long time, such as while syncing, and the
+// readiness probe already keeps it out of the traffic.
+func defaultStartupProbe(port int32, window time.Duration) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/health/liveness",
+				Port: intstr.FromInt32(port),
+			},
+		},
+		FailureThreshold: int32(window / (startupProbePeriodSeconds * time.Second)),
+		PeriodSeconds:    startupProbePeriodSeconds,
+	}
+}
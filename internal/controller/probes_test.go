@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Probes", func() {
+	ctx := context.Background()
+
+	It("should apply probe overrides to the service container", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Legacy.Spec.DeploymentOverrides = &teranodev1alpha1.DeploymentOverrides{
+				LivenessProbe: &teranodev1alpha1.ProbeOverride{
+					Disabled: true,
+				},
+				StartupProbe: &teranodev1alpha1.ProbeOverride{
+					Probe: v1.Probe{
+						FailureThreshold: 4320,
+					},
+				},
+				ReadinessProbe: &teranodev1alpha1.ProbeOverride{
+					Probe: v1.Probe{
+						ProbeHandler: v1.ProbeHandler{
+							TCPSocket: &v1.TCPSocketAction{
+								Port: intstr.FromInt32(LegacyHTTPPort),
+							},
+						},
+						PeriodSeconds: 15,
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &LegacyReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "legacy")
+		Expect(err).NotTo(HaveOccurred())
+
+		dep := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "legacy", dep)).To(Succeed())
+		container := dep.Spec.Template.Spec.Containers[0]
+		Expect(container.LivenessProbe).To(BeNil())
+		// the threshold is overridden, the handler of the default is kept
+		Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(4320)))
+		Expect(container.StartupProbe.HTTPGet.Path).To(Equal("/health/liveness"))
+		// a handler of another kind replaces the default one
+		Expect(container.ReadinessProbe.HTTPGet).To(BeNil())
+		Expect(container.ReadinessProbe.TCPSocket.Port).To(Equal(intstr.FromInt32(LegacyHTTPPort)))
+		Expect(container.ReadinessProbe.PeriodSeconds).To(Equal(int32(15)))
+	})
+
+	It("should only restart a service that stops answering for a minute once it started", func() {
+		cluster := newTestCluster(ctx, nil)
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &BlockchainReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "blockchain")
+		Expect(err).NotTo(HaveOccurred())
+
+		dep := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, BlockchainDeploymentName, dep)).To(Succeed())
+		container := dep.Spec.Template.Spec.Containers[0]
+		startup := container.StartupProbe
+		Expect(time.Duration(startup.PeriodSeconds*startup.FailureThreshold) * time.Second).To(Equal(BlockchainStartupWindow))
+		liveness := container.LivenessProbe
+		Expect(liveness.HTTPGet.Path).To(Equal("/health/liveness"))
+		Expect(liveness.PeriodSeconds * liveness.FailureThreshold).To(BeNumerically(">=", 60))
+		Expect(liveness.TimeoutSeconds).To(BeNumerically(">", 3))
+	})
+})
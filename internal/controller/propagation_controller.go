@@ -27,6 +27,7 @@ import (
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -80,7 +81,12 @@ func (r *PropagationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	_, err = utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
+		r.ReconcileQuicExposure,
 		r.ReconcileGrpcIngress,
+		r.ReconcileHTTPIngress,
+		r.ReconcileDelveIngress,
+		r.ReconcileProfilerIngress,
 	)
 
 	// Update scale status (replicas and selector) from deployment
@@ -109,6 +115,10 @@ func (r *PropagationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		r.Log.Error(err, "requeuing object for reconciliation")
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	} else {
+		propagation.Status.ExternalAddresses, err = exposureAddresses(ctx, r.Client, req.Namespace, quicExposure(&propagation), "propagation", propagationQuicPorts())
+		if err != nil {
+			r.Log.Error(err, "unable to resolve external addresses")
+		}
 		apimeta.SetStatusCondition(&propagation.Status.Conditions,
 			metav1.Condition{
 				Type:    teranodev1alpha1.ConditionReconciled,
@@ -119,6 +129,12 @@ func (r *PropagationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		)
 	}
 
+	certificates, certErr := certificateStatuses(ctx, r.Client, &propagation)
+	if certErr != nil {
+		r.Log.Error(certErr, "unable to fetch certificates")
+	}
+	propagation.Status.Certificates = certificates
+
 	_ = r.Status().Update(ctx, &propagation)
 
 	if propagation.Spec.DeploymentOverrides != nil && propagation.Spec.DeploymentOverrides.Replicas != nil {
@@ -128,16 +144,23 @@ func (r *PropagationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
+	if !certificatesReady(propagation.Status.Certificates) {
+		return ctrl.Result{RequeueAfter: CertificateRequeueInterval}, nil
+	}
+
 	return ctrl.Result{RequeueAfter: 0}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PropagationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&teranodev1alpha1.Propagation{}).
+		// only the spec and annotations of the propagation trigger a reconcile, while the owned objects always do,
+		// so that the address a load balancer gets is written to the status
+		For(&teranodev1alpha1.Propagation{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
 		Owns(&appsv1.Deployment{}).
 		Owns(&networkingv1.Ingress{}).
 		Owns(&corev1.Service{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
 		Complete(r)
 }
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// ReconcileQuicExposure is the reconciler for the service exposing the propagation QUIC port outside the cluster.
+// QUIC runs over UDP, so it goes through a LoadBalancer or NodePort service instead of an ingress.
+func (r *PropagationReconciler) ReconcileQuicExposure(log logr.Logger) (bool, error) {
+	propagation := teranodev1alpha1.Propagation{}
+	if err := r.Get(r.Context, r.NamespacedName, &propagation); err != nil {
+		return false, err
+	}
+	err := reconcileExposureService(r.Context, r.Client, r.Scheme, &propagation, quicExposure(&propagation), "propagation", propagationQuicPorts())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// quicExposure returns the QUIC exposure, falling back to a LoadBalancer for the deprecated QuicIngress
+func quicExposure(propagation *teranodev1alpha1.Propagation) *teranodev1alpha1.ExposureDef {
+	if propagation.Spec.QuicExposure != nil {
+		return propagation.Spec.QuicExposure
+	}
+	if propagation.Spec.QuicIngress != nil {
+		return &teranodev1alpha1.ExposureDef{
+			Type:        teranodev1alpha1.ExposureTypeLoadBalancer,
+			Annotations: propagation.Spec.QuicIngress.Annotations,
+		}
+	}
+	return nil
+}
+
+func propagationQuicPorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name:       "propagation-quic",
+			Port:       int32(PropagationQuicPort),
+			TargetPort: intstr.FromInt32(PropagationQuicPort),
+			Protocol:   corev1.ProtocolUDP,
+		},
+	}
+}
+
+// setQuicProtocol moves the QUIC container port to UDP on deployments created before QUIC was served over UDP
+func setQuicProtocol(podSpec *corev1.PodSpec) {
+	for i := range podSpec.Containers {
+		for j := range podSpec.Containers[i].Ports {
+			if podSpec.Containers[i].Ports[j].ContainerPort == PropagationQuicPort {
+				podSpec.Containers[i].Ports[j].Protocol = corev1.ProtocolUDP
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Propagation exposure", func() {
+	ctx := context.Background()
+
+	It("should serve propagation QUIC over UDP", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Propagation.Spec = &teranodev1alpha1.PropagationSpec{
+				QuicIngress: &teranodev1alpha1.IngressDef{
+					Host: "quic.example.com",
+					Annotations: map[string]string{
+						"service.beta.kubernetes.io/aws-load-balancer-type": "nlb",
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &PropagationReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "propagation")
+		Expect(err).NotTo(HaveOccurred())
+
+		dep := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, PropagationDeploymentName, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Ports).To(ContainElement(v1.ContainerPort{
+			ContainerPort: PropagationQuicPort,
+			Protocol:      v1.ProtocolUDP,
+		}))
+
+		svc := &v1.Service{}
+		Expect(getTestObject(ctx, cluster, "propagation", svc)).To(Succeed())
+		Expect(svc.Spec.Ports).To(ContainElement(And(
+			HaveField("Port", int32(PropagationQuicPort)),
+			HaveField("Protocol", v1.ProtocolUDP),
+		)))
+
+		// an Ingress can't carry UDP, so QUIC gets a LoadBalancer of its own
+		external := &v1.Service{}
+		Expect(getTestObject(ctx, cluster, "propagation"+ExternalServiceSuffix, external)).To(Succeed())
+		Expect(external.Spec.Type).To(Equal(v1.ServiceTypeLoadBalancer))
+		Expect(external.Spec.Ports).To(HaveLen(1))
+		Expect(external.Spec.Ports[0].Protocol).To(Equal(v1.ProtocolUDP))
+		Expect(external.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-type", "nlb"))
+	})
+})
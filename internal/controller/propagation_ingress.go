@@ -2,9 +2,6 @@ package controller
 
 import (
 	"github.com/go-logr/logr"
-	v1 "k8s.io/api/networking/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
 )
@@ -15,70 +12,82 @@ func (r *PropagationReconciler) ReconcileGrpcIngress(log logr.Logger) (bool, err
 	if err := r.Get(r.Context, r.NamespacedName, &propagation); err != nil {
 		return false, err
 	}
-	// Skip if GrpcIngress isn't set
 	if propagation.Spec.GrpcIngress == nil {
-		return false, nil
+		return true, deleteIngress(r.Context, r.Client, &propagation, "propagation-grpc")
 	}
-
-	labels := getAppLabels("propagation")
-	prefix := v1.PathTypePrefix
-	ingress := &v1.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        "propagation-grpc",
-			Namespace:   r.NamespacedName.Namespace,
-			Annotations: propagation.Spec.GrpcIngress.Annotations,
-			Labels:      labels,
-		},
-		Spec: v1.IngressSpec{
-			Rules: []v1.IngressRule{
-				{
-					Host: propagation.Spec.GrpcIngress.Host,
-					IngressRuleValue: v1.IngressRuleValue{
-						HTTP: &v1.HTTPIngressRuleValue{
-							Paths: []v1.HTTPIngressPath{
-								{
-									Path:     "/",
-									PathType: &prefix,
-									Backend: v1.IngressBackend{
-										Service: &v1.IngressServiceBackend{
-											Name: "propagation",
-											Port: v1.ServiceBackendPort{
-												Number: PropagationGRPCPort,
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &propagation, propagation.Spec.GrpcIngress, routeTarget{
+		Name:        "propagation-grpc",
+		App:         "propagation",
+		ServiceName: "propagation",
+		Port:        PropagationGRPCPort,
+		RouteType:   teranodev1alpha1.RouteTypeGRPC,
+	}); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, ingress, func() error {
-		return r.updateGrpcIngress(ingress, &propagation)
-	})
-	if err != nil {
+// ReconcileHTTPIngress is the ingress for the propagation http server
+func (r *PropagationReconciler) ReconcileHTTPIngress(log logr.Logger) (bool, error) {
+	propagation := teranodev1alpha1.Propagation{}
+	if err := r.Get(r.Context, r.NamespacedName, &propagation); err != nil {
+		return false, err
+	}
+	if propagation.Spec.HTTPIngress == nil {
+		return true, deleteIngress(r.Context, r.Client, &propagation, "propagation-http")
+	}
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &propagation, propagation.Spec.HTTPIngress, routeTarget{
+		Name:        "propagation-http",
+		App:         "propagation",
+		ServiceName: "propagation",
+		Port:        PropagationHTTPPort,
+		RouteType:   teranodev1alpha1.RouteTypeHTTP,
+	}); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func (r *PropagationReconciler) updateGrpcIngress(ingress *v1.Ingress, propagation *teranodev1alpha1.Propagation) error {
-	err := controllerutil.SetControllerReference(propagation, ingress, r.Scheme)
-	if err != nil {
-		return err
+// ReconcileDelveIngress is the ingress for the propagation delve debugger, which must be restricted
+func (r *PropagationReconciler) ReconcileDelveIngress(log logr.Logger) (bool, error) {
+	propagation := teranodev1alpha1.Propagation{}
+	if err := r.Get(r.Context, r.NamespacedName, &propagation); err != nil {
+		return false, err
 	}
-	if propagation.Spec.GrpcIngress == nil {
-		return nil
+	if propagation.Spec.DelveIngress == nil {
+		return true, deleteIngress(r.Context, r.Client, &propagation, "propagation-delve")
+	}
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &propagation, propagation.Spec.DelveIngress, routeTarget{
+		Name:        "propagation-delve",
+		App:         "propagation",
+		ServiceName: "propagation",
+		Port:        DebuggerPort,
+		RouteType:   teranodev1alpha1.RouteTypeHTTP,
+		Debug:       true,
+	}); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	if propagation.Spec.GrpcIngress.Annotations == nil {
-		ingress.Annotations = propagation.Spec.GrpcIngress.Annotations
+// ReconcileProfilerIngress is the ingress for the propagation profiler, which must be restricted
+func (r *PropagationReconciler) ReconcileProfilerIngress(log logr.Logger) (bool, error) {
+	propagation := teranodev1alpha1.Propagation{}
+	if err := r.Get(r.Context, r.NamespacedName, &propagation); err != nil {
+		return false, err
 	}
-	if propagation.Spec.GrpcIngress.ClassName != nil {
-		ingress.Spec.IngressClassName = propagation.Spec.GrpcIngress.ClassName
+	if propagation.Spec.ProfilerIngress == nil {
+		return true, deleteIngress(r.Context, r.Client, &propagation, "propagation-profiler")
 	}
-	return nil
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &propagation, propagation.Spec.ProfilerIngress, routeTarget{
+		Name:        "propagation-profiler",
+		App:         "propagation",
+		ServiceName: "propagation",
+		Port:        ProfilerPort,
+		RouteType:   teranodev1alpha1.RouteTypeHTTP,
+		Debug:       true,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
 }
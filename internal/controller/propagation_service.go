@@ -39,12 +39,7 @@ func (r *PropagationReconciler) updateService(svc *corev1.Service, propagation *
 		return err
 	}
 	svc.Spec = *defaultPropagationServiceSpec()
-	if svc.Annotations == nil {
-		svc.Annotations = map[string]string{}
-	}
-	for k, v := range propagation.Spec.ServiceAnnotations {
-		svc.Annotations[k] = v
-	}
+	utils.SetServiceOverrides(r.Client, svc, propagation)
 	return nil
 }
 
@@ -71,7 +66,7 @@ func defaultPropagationServiceSpec() *corev1.ServiceSpec {
 				Name:       "propagation-quic",
 				Port:       int32(PropagationQuicPort),
 				TargetPort: intstr.FromInt32(PropagationQuicPort),
-				Protocol:   corev1.ProtocolTCP,
+				Protocol:   corev1.ProtocolUDP,
 			},
 			{
 				Name:       "propagation-http",
@@ -85,6 +80,30 @@ func defaultPropagationServiceSpec() *corev1.ServiceSpec {
 				TargetPort: intstr.FromInt32(HealthPort),
 				Protocol:   corev1.ProtocolTCP,
 			},
+			{
+				Name:       "delve",
+				Port:       int32(DebuggerPort),
+				TargetPort: intstr.FromInt32(DebuggerPort),
+				Protocol:   corev1.ProtocolTCP,
+			},
+			{
+				Name:       "profiler",
+				Port:       int32(ProfilerPort),
+				TargetPort: intstr.FromInt32(ProfilerPort),
+				Protocol:   corev1.ProtocolTCP,
+			},
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *PropagationReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	propagation := teranodev1alpha1.Propagation{}
+	if err := r.Get(r.Context, r.NamespacedName, &propagation); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &propagation, "propagation", "propagation"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
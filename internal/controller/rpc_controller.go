@@ -71,8 +71,12 @@ func (r *RPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	_, err := utils.ReconcileBatch(r.Log,
+		r.ReconcileCredentials,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
+		r.ReconcileIngress,
+		r.ReconcileExposure,
 	)
 
 	if err != nil {
@@ -90,6 +94,10 @@ func (r *RPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		r.Log.Error(err, "requeuing object for reconciliation")
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second}, err
 	} else {
+		rpc.Status.ExternalAddresses, err = exposureAddresses(ctx, r.Client, req.Namespace, rpc.Spec.Exposure, "rpc", rpcExposurePorts())
+		if err != nil {
+			r.Log.Error(err, "unable to resolve external addresses")
+		}
 		apimeta.SetStatusCondition(&rpc.Status.Conditions,
 			metav1.Condition{
 				Type:    teranodev1alpha1.ConditionReconciled,
@@ -100,7 +108,17 @@ func (r *RPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		)
 	}
 
+	certificates, certErr := certificateStatuses(ctx, r.Client, &rpc)
+	if certErr != nil {
+		r.Log.Error(certErr, "unable to fetch certificates")
+	}
+	rpc.Status.Certificates = certificates
+	rpc.Status.CredentialsRotatedAt = credentialsRotatedAt(ctx, r.Client, req.Namespace)
+
 	err = r.Client.Status().Update(ctx, &rpc)
+	if !certificatesReady(rpc.Status.Certificates) {
+		return ctrl.Result{RequeueAfter: CertificateRequeueInterval}, err
+	}
 	return ctrl.Result{Requeue: false, RequeueAfter: 0}, err
 }
 
@@ -110,5 +128,6 @@ func (r *RPCReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&teranodev1alpha1.RPC{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
 		Complete(r)
 }
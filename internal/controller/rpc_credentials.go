@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// Secrets generated for the RPC credentials
+const (
+	RPCCredentialsSecretName       = "rpc-credentials"
+	DefaultRPCConnectionSecret     = "rpc-connection"
+	DefaultRPCUsername             = "teranode"
+	RPCUsernameKey                 = "username"
+	RPCPasswordKey                 = "password"
+	rpcPasswordBytes               = 24
+	credentialsRotatedAnnotation   = "teranode.bsvblockchain.org/rotated-for"
+	credentialsRotatedAtAnnotation = "teranode.bsvblockchain.org/rotated-at"
+	// CredentialsChecksumAnnotation rolls the RPC pods when the credentials change
+	CredentialsChecksumAnnotation = "teranode.bsvblockchain.org/credentials-checksum"
+	// RPCConnectionLabel marks the connection secrets, so that they are deleted with the credentials or once renamed
+	RPCConnectionLabel = "teranode.bsvblockchain.org/rpc-connection"
+)
+
+// ReconcileCredentials generates the RPC username and password, and publishes them in the connection secret
+func (r *RPCReconciler) ReconcileCredentials(log logr.Logger) (bool, error) {
+	rpc := teranodev1alpha1.RPC{}
+	if err := r.Get(r.Context, r.NamespacedName, &rpc); err != nil {
+		return false, err
+	}
+	credentials := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RPCCredentialsSecretName,
+			Namespace: r.NamespacedName.Namespace,
+			Labels:    getAppLabels("rpc"),
+		},
+	}
+	if rpc.Spec.Credentials == nil {
+		if err := r.Delete(r.Context, &credentials); client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+		return true, r.deleteConnections(&rpc, "")
+	}
+	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &credentials, func() error {
+		return r.updateCredentials(&credentials, &rpc)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	connection := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rpcConnectionSecretName(&rpc),
+			Namespace: r.NamespacedName.Namespace,
+			Labels:    getAppLabels("rpc"),
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(r.Context, r.Client, &connection, func() error {
+		return r.updateConnection(&connection, &credentials, &rpc)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, r.deleteConnections(&rpc, connection.Name)
+}
+
+// deleteConnections deletes the connection secrets of the RPC other than the current one
+func (r *RPCReconciler) deleteConnections(rpc *teranodev1alpha1.RPC, current string) error {
+	secrets := &corev1.SecretList{}
+	err := r.List(r.Context, secrets, client.InNamespace(rpc.Namespace), client.MatchingLabels{RPCConnectionLabel: "true"})
+	if err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Name == current || !metav1.IsControlledBy(secret, rpc) {
+			continue
+		}
+		if err := r.Delete(r.Context, secret); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RPCReconciler) updateCredentials(secret *corev1.Secret, rpc *teranodev1alpha1.RPC) error {
+	err := controllerutil.SetControllerReference(rpc, secret, r.Scheme)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Type = corev1.SecretTypeOpaque
+	secret.Data[RPCUsernameKey] = []byte(rpcUsername(rpc))
+
+	rotation := rpc.Annotations[teranodev1alpha1.RotateCredentialsAnnotation]
+	if len(secret.Data[RPCPasswordKey]) > 0 && secret.Annotations[credentialsRotatedAnnotation] == rotation {
+		return nil
+	}
+	password, err := generatePassword()
+	if err != nil {
+		return err
+	}
+	secret.Data[RPCPasswordKey] = []byte(password)
+	secret.Annotations[credentialsRotatedAnnotation] = rotation
+	secret.Annotations[credentialsRotatedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+func (r *RPCReconciler) updateConnection(secret *corev1.Secret, credentials *corev1.Secret, rpc *teranodev1alpha1.RPC) error {
+	err := controllerutil.SetControllerReference(rpc, secret, r.Scheme)
+	if err != nil {
+		return err
+	}
+	secret.Type = corev1.SecretTypeOpaque
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[RPCConnectionLabel] = "true"
+	username := string(credentials.Data[RPCUsernameKey])
+	password := string(credentials.Data[RPCPasswordKey])
+	host := fmt.Sprintf("rpc.%s.svc", rpc.Namespace)
+	port := strconv.Itoa(RPCPort)
+	endpoint := url.URL{
+		Scheme: "http",
+		User:   url.UserPassword(username, password),
+		Host:   net.JoinHostPort(host, port),
+	}
+	secret.Data = map[string][]byte{
+		RPCUsernameKey: []byte(username),
+		RPCPasswordKey: []byte(password),
+		"host":         []byte(host),
+		"port":         []byte(port),
+		"url":          []byte(endpoint.String()),
+	}
+	if rpc.Spec.Ingress != nil {
+		def := resolveIngressHost(endpointCluster(r.Context, r.Client, rpc), rpc.Spec.Ingress, "rpc")
+		if def.Host != "" {
+			// the credentials are only part of the URL over TLS, so that they aren't sent in clear over the internet
+			external := url.URL{
+				Scheme: "http",
+				Host:   def.Host,
+			}
+			if def.TLS != nil {
+				external.Scheme = "https"
+				external.User = url.UserPassword(username, password)
+			}
+			secret.Data["externalUrl"] = []byte(external.String())
+		}
+	}
+	return nil
+}
+
+func rpcUsername(rpc *teranodev1alpha1.RPC) string {
+	if rpc.Spec.Credentials.Username != "" {
+		return rpc.Spec.Credentials.Username
+	}
+	return DefaultRPCUsername
+}
+
+func rpcConnectionSecretName(rpc *teranodev1alpha1.RPC) string {
+	if rpc.Spec.Credentials.ConnectionSecretName != "" {
+		return rpc.Spec.Credentials.ConnectionSecretName
+	}
+	return DefaultRPCConnectionSecret
+}
+
+func generatePassword() (string, error) {
+	b := make([]byte, rpcPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// setRPCCredentials injects the generated credentials into the RPC container, and rolls the pods when they change
+func (r *RPCReconciler) setRPCCredentials(ctx context.Context, dep *corev1.PodTemplateSpec, rpc *teranodev1alpha1.RPC) error {
+	if rpc.Spec.Credentials == nil || len(dep.Spec.Containers) == 0 {
+		return nil
+	}
+	secret := corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: RPCCredentialsSecretName, Namespace: rpc.Namespace}, &secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	container := &dep.Spec.Containers[0]
+	for _, setting := range []struct{ name, key string }{
+		{RPCUserSetting, RPCUsernameKey},
+		{RPCPassSetting, RPCPasswordKey},
+	} {
+		if hasEnv(container, setting.name) {
+			continue
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: setting.name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: RPCCredentialsSecretName},
+					Key:                  setting.key,
+				},
+			},
+		})
+	}
+	checksum := sha256.Sum256(append(secret.Data[RPCUsernameKey], secret.Data[RPCPasswordKey]...))
+	if dep.Annotations == nil {
+		dep.Annotations = map[string]string{}
+	}
+	dep.Annotations[CredentialsChecksumAnnotation] = hex.EncodeToString(checksum[:8])
+	return nil
+}
+
+// credentialsRotatedAt returns when the RPC password was last generated
+func credentialsRotatedAt(ctx context.Context, c client.Client, namespace string) *metav1.Time {
+	secret := corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: RPCCredentialsSecretName, Namespace: namespace}, &secret); err != nil {
+		return nil
+	}
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[credentialsRotatedAtAnnotation])
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: rotatedAt}
+}
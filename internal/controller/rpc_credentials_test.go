@@ -0,0 +1,116 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("RPC credentials", func() {
+	ctx := context.Background()
+
+	It("should generate and rotate RPC credentials", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.RPC.Spec = &teranodev1alpha1.RPCSpec{
+				Ingress: &teranodev1alpha1.IngressDef{
+					Host:                "rpc.example.com",
+					AllowedSourceRanges: []string{"10.0.0.0/8"},
+					TLS:                 &teranodev1alpha1.IngressTLS{},
+				},
+				Credentials: &teranodev1alpha1.RPCCredentials{
+					Username: "operator",
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		rpcReconciler := &RPCReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, rpcReconciler, cluster, "rpc")
+		Expect(err).NotTo(HaveOccurred())
+
+		credentials := &v1.Secret{}
+		Expect(getTestObject(ctx, cluster, RPCCredentialsSecretName, credentials)).To(Succeed())
+		Expect(string(credentials.Data[RPCUsernameKey])).To(Equal("operator"))
+		password := string(credentials.Data[RPCPasswordKey])
+		Expect(password).To(HaveLen(48))
+
+		connection := &v1.Secret{}
+		Expect(getTestObject(ctx, cluster, DefaultRPCConnectionSecret, connection)).To(Succeed())
+		Expect(string(connection.Data["url"])).To(Equal(fmt.Sprintf("http://operator:%s@rpc.%s.svc:9292", password, cluster.Namespace)))
+		Expect(string(connection.Data["externalUrl"])).To(Equal(fmt.Sprintf("https://operator:%s@rpc.example.com", password)))
+
+		deployment := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "rpc", deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElement(v1.EnvVar{
+			Name: RPCPassSetting,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: RPCCredentialsSecretName},
+					Key:                  RPCPasswordKey,
+				},
+			},
+		}))
+		checksum := deployment.Spec.Template.Annotations[CredentialsChecksumAnnotation]
+		Expect(checksum).NotTo(BeEmpty())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(getTestObject(ctx, cluster, "rpc", ingress)).To(Succeed())
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number).To(Equal(int32(RPCPort)))
+
+		// rotating the password rolls the pods
+		rpc := &teranodev1alpha1.RPC{}
+		rpc.Name, rpc.Namespace = testServiceName(cluster, "rpc").Name, cluster.Namespace
+		Expect(updateTestObject(ctx, rpc, func() {
+			rpc.Annotations = map[string]string{teranodev1alpha1.RotateCredentialsAnnotation: "1"}
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, rpcReconciler, cluster, "rpc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTestObject(ctx, cluster, RPCCredentialsSecretName, credentials)).To(Succeed())
+		Expect(string(credentials.Data[RPCPasswordKey])).NotTo(Equal(password))
+		Expect(getTestObject(ctx, cluster, "rpc", deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Annotations[CredentialsChecksumAnnotation]).NotTo(Equal(checksum))
+
+		// without TLS the credentials are left out of the external URL, and a renamed connection secret replaces the previous one
+		Expect(updateTestObject(ctx, rpc, func() {
+			rpc.Spec.Ingress.TLS = nil
+			rpc.Spec.Credentials.ConnectionSecretName = "rpc-client"
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, rpcReconciler, cluster, "rpc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTestObject(ctx, cluster, "rpc-client", connection)).To(Succeed())
+		Expect(string(connection.Data["externalUrl"])).To(Equal("http://rpc.example.com"))
+		Expect(errors.IsNotFound(getTestObject(ctx, cluster, DefaultRPCConnectionSecret, connection))).To(BeTrue())
+
+		// removing the credentials deletes both secrets
+		Expect(updateTestObject(ctx, rpc, func() {
+			rpc.Spec.Credentials = nil
+		})).To(Succeed())
+		_, err = reconcileTestService(ctx, rpcReconciler, cluster, "rpc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(getTestObject(ctx, cluster, RPCCredentialsSecretName, credentials))).To(BeTrue())
+		Expect(errors.IsNotFound(getTestObject(ctx, cluster, "rpc-client", connection))).To(BeTrue())
+	})
+})
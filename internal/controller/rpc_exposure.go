@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// ReconcileIngress is the ingress for the rpc server
+func (r *RPCReconciler) ReconcileIngress(log logr.Logger) (bool, error) {
+	rpc := teranodev1alpha1.RPC{}
+	if err := r.Get(r.Context, r.NamespacedName, &rpc); err != nil {
+		return false, err
+	}
+	if rpc.Spec.Ingress == nil {
+		return true, nil
+	}
+	if err := reconcileIngress(r.Context, r.Client, r.Scheme, &rpc, rpc.Spec.Ingress, routeTarget{
+		Name:        "rpc",
+		App:         "rpc",
+		ServiceName: "rpc",
+		Port:        RPCPort,
+		RouteType:   teranodev1alpha1.RouteTypeHTTP,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReconcileExposure is the reconciler for the service exposing the rpc port outside the cluster
+func (r *RPCReconciler) ReconcileExposure(log logr.Logger) (bool, error) {
+	rpc := teranodev1alpha1.RPC{}
+	if err := r.Get(r.Context, r.NamespacedName, &rpc); err != nil {
+		return false, err
+	}
+	err := reconcileExposureService(r.Context, r.Client, r.Scheme, &rpc, rpc.Spec.Exposure, "rpc", rpcExposurePorts())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func rpcExposurePorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name:       "rpc",
+			Port:       int32(RPCPort),
+			TargetPort: intstr.FromInt32(RPCPort),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+}
@@ -39,6 +39,7 @@ func (r *RPCReconciler) updateService(svc *corev1.Service, rpc *teranodev1alpha1
 		return err
 	}
 	svc.Spec = *defaultRPCServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, rpc)
 	return nil
 }
 
@@ -70,3 +71,15 @@ func defaultRPCServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *RPCReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	rpc := teranodev1alpha1.RPC{}
+	if err := r.Get(r.Context, r.NamespacedName, &rpc); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &rpc, "rpc", "rpc"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
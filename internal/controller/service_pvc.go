@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Data paths the dedicated storage of a service is mounted at, below the shared storage. A dedicated PVC is
+// only mounted by its service, so none of them is a path other services read, such as the block store.
+const (
+	BlockAssemblyDataPath  = "/data/blockassembly"
+	BlockchainDataPath     = "/data/blockchain"
+	BlockPersisterDataPath = "/data/block-persister"
+	LegacyDataPath         = "/data/legacy"
+	UtxoPersisterDataPath  = "/data/utxo-persister"
+)
+
+var (
+	// ErrWorkloadPodsRunning is returned while the pods of the previous workload of a service are still running,
+	// since its data claim can only be moved once they are gone
+	ErrWorkloadPodsRunning = errors.New("waiting for the pods of the previous workload to terminate before moving its data")
+	// ErrStatefulSetClaim is returned when a StatefulSet without dedicated storage would switch back to a
+	// Deployment, which would leave the data of its first replica behind
+	ErrStatefulSetClaim = errors.New("switching back to a Deployment would leave the claim of the first replica behind, " +
+		"set storageClass or storageResources to move it to the dedicated PVC, or delete it")
+)
+
+// DefaultServiceStorageSize is the size of a dedicated PVC that sets a storage class but no size
+const DefaultServiceStorageSize = "100Gi"
+
+// ReclaimPolicyAnnotation records the reclaim policy of a volume while it's retained to move between claims
+const ReclaimPolicyAnnotation = "teranode.bsvblockchain.org/reclaim-policy"
+
+// servicePVCName is the dedicated PVC of an app
+func servicePVCName(app string) string {
+	return app + "-storage"
+}
+
+// reconcileServicePVC creates the dedicated PVC of a service running as a Deployment. Like the shared PVC,
+// the existing spec is kept so that only the storage class and resources change, which resizes the volume.
+func reconcileServicePVC(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner, w workload) (bool, error) {
+	if !w.dedicatedStorage() || w.statefulSet() {
+		return true, nil
+	}
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      servicePVCName(w.App),
+			Namespace: owner.GetNamespace(),
+			Labels:    getAppLabels(w.App),
+		},
+	}
+	existingPVC := &corev1.PersistentVolumeClaim{}
+	err := c.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, existingPVC)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+	if k8serrors.IsNotFound(err) {
+		existingPVC = nil
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, c, &pvc, func() error {
+		if err := controllerutil.SetControllerReference(owner, &pvc, scheme); err != nil {
+			return err
+		}
+		if existingPVC == nil {
+			pvc.Spec = *w.pvcSpec()
+			return nil
+		}
+		pvc.Spec = *existingPVC.Spec.DeepCopy()
+		// a claim can only be resized once it's bound
+		if w.StorageResources != nil && existingPVC.Status.Phase == corev1.ClaimBound {
+			pvc.Spec.Resources = *w.pvcSpec().Resources.DeepCopy()
+		}
+		return nil
+	})
+
+	// Ignore forbidden errors, such as shrinking the volume or a storage class that can't expand
+	if err != nil && !k8serrors.IsForbidden(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// mountServicePVC mounts the dedicated PVC of a service at its data path
+func mountServicePVC(podSpec *corev1.PodSpec, app string, mountPath string) {
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: servicePVCName(app),
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: servicePVCName(app),
+			},
+		},
+	})
+	setVolumeMount(&podSpec.Containers[0], corev1.VolumeMount{
+		Name:      servicePVCName(app),
+		MountPath: mountPath,
+	})
+}
+
+// setVolumeMount mounts the volume, replacing what was mounted at the same path, such as a store volume
+// of the cluster at the data path of the service
+func setVolumeMount(container *corev1.Container, mount corev1.VolumeMount) {
+	mounts := container.VolumeMounts[:0]
+	for _, m := range container.VolumeMounts {
+		if m.MountPath != mount.MountPath {
+			mounts = append(mounts, m)
+		}
+	}
+	container.VolumeMounts = append(mounts, mount)
+}
+
+// migrateClaim moves the volume bound to a claim to a new claim, so that the data of a service follows it when it
+// switches between a Deployment and a StatefulSet. It waits for the pods of the app to be gone, so that the new
+// workload doesn't start on an empty claim of its own. The volume is retained, and reserved for the new claim
+// before the old one is deleted. Its reclaim policy is restored once the new claim is bound.
+func migrateClaim(ctx context.Context, c client.Client, namespace string, app string, from string,
+	to *corev1.PersistentVolumeClaim) error {
+	existing := &corev1.PersistentVolumeClaim{}
+	err := c.Get(ctx, types.NamespacedName{Name: to.Name, Namespace: namespace}, existing)
+	if err == nil {
+		// the data was already moved, or the new claim has its own
+		return restoreReclaimPolicy(ctx, c, existing)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+	old := &corev1.PersistentVolumeClaim{}
+	err = c.Get(ctx, types.NamespacedName{Name: from, Namespace: namespace}, old)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !old.DeletionTimestamp.IsZero() || old.Spec.VolumeName == "" {
+		return nil
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(getAppLabels(app))); err != nil {
+		return err
+	}
+	if len(pods.Items) > 0 {
+		return fmt.Errorf("%w: %s", ErrWorkloadPodsRunning, app)
+	}
+
+	pv := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, types.NamespacedName{Name: old.Spec.VolumeName}, pv); err != nil {
+		return err
+	}
+	if _, ok := pv.Annotations[ReclaimPolicyAnnotation]; !ok {
+		if pv.Annotations == nil {
+			pv.Annotations = map[string]string{}
+		}
+		pv.Annotations[ReclaimPolicyAnnotation] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	pv.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Namespace:  namespace,
+		Name:       to.Name,
+	}
+	if err := c.Update(ctx, pv); err != nil {
+		return err
+	}
+
+	to.Namespace = namespace
+	to.Spec = *old.Spec.DeepCopy()
+	to.Spec.VolumeName = pv.Name
+	to.Spec.DataSource = nil
+	to.Spec.DataSourceRef = nil
+	if err := c.Create(ctx, to); err != nil {
+		return err
+	}
+	return client.IgnoreNotFound(c.Delete(ctx, old))
+}
+
+// restoreReclaimPolicy sets back the reclaim policy recorded by migrateClaim, once the claim the volume was moved to
+// is bound
+func restoreReclaimPolicy(ctx context.Context, c client.Client, claim *corev1.PersistentVolumeClaim) error {
+	if claim.Status.Phase != corev1.ClaimBound || claim.Spec.VolumeName == "" {
+		return nil
+	}
+	pv := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, types.NamespacedName{Name: claim.Spec.VolumeName}, pv); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	policy, ok := pv.Annotations[ReclaimPolicyAnnotation]
+	if !ok {
+		return nil
+	}
+	if policy != "" {
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimPolicy(policy)
+	}
+	delete(pv.Annotations, ReclaimPolicyAnnotation)
+	return c.Update(ctx, pv)
+}
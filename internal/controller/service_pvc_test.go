@@ -0,0 +1,71 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Service storage", func() {
+	ctx := context.Background()
+
+	It("should create dedicated storage for the block persister", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.BlockPersister.Spec = &teranodev1alpha1.BlockPersisterSpec{
+				StorageClass: "gp3",
+				StorageResources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		_, err := reconcileTestService(ctx, &BlockPersisterReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, cluster, "blockpersister")
+		Expect(err).NotTo(HaveOccurred())
+
+		pvc := &v1.PersistentVolumeClaim{}
+		Expect(getTestObject(ctx, cluster, servicePVCName("block-persister"), pvc)).To(Succeed())
+		Expect(*pvc.Spec.StorageClassName).To(Equal("gp3"))
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(v1.ReadWriteOnce))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+
+		// it's mounted outside the shared block store, which it would otherwise hide
+		deployment := &appsv1.Deployment{}
+		Expect(getTestObject(ctx, cluster, "block-persister", deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      servicePVCName("block-persister"),
+			MountPath: BlockPersisterDataPath,
+		}))
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(v1.Volume{
+			Name: servicePVCName("block-persister"),
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: servicePVCName("block-persister"),
+				},
+			},
+		}))
+	})
+})
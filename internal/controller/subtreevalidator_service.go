@@ -39,6 +39,7 @@ func (r *SubtreeValidatorReconciler) updateService(svc *corev1.Service, subtreeV
 		return err
 	}
 	svc.Spec = *defaultSubtreeValidatorServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, subtreeValidator)
 	return nil
 }
 
@@ -76,3 +77,15 @@ func defaultSubtreeValidatorServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *SubtreeValidatorReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	subtreeValidator := teranodev1alpha1.SubtreeValidator{}
+	if err := r.Get(r.Context, r.NamespacedName, &subtreeValidator); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &subtreeValidator, "subtree-validator", "subtree-validator"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -68,6 +68,7 @@ func (r *UtxoPersisterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	_, err := utils.ReconcileBatch(r.Log,
 		r.ReconcileDeployment,
 		r.ReconcileService,
+		r.ReconcileIngresses,
 	)
 
 	if err != nil {
@@ -103,5 +104,6 @@ func (r *UtxoPersisterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&teranodev1alpha1.UtxoPersister{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
 		Complete(r)
 }
@@ -6,7 +6,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -24,20 +23,9 @@ func (r *UtxoPersisterReconciler) ReconcileDeployment(log logr.Logger) (bool, er
 	if err := r.Get(r.Context, r.NamespacedName, &up); err != nil {
 		return false, err
 	}
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      UtxoPersisterName,
-			Namespace: r.NamespacedName.Namespace,
-			Labels:    getAppLabels("utxo-persister"),
-		},
-	}
-	_, err := controllerutil.CreateOrUpdate(r.Context, r.Client, &dep, func() error {
-		return r.updateDeployment(&dep, &up)
+	return reconcileWorkload(r.Context, r.Client, r.Scheme, &up, utxoPersisterWorkload(&up), func(dep *appsv1.Deployment) error {
+		return r.updateDeployment(dep, &up)
 	})
-	if err != nil {
-		return false, err
-	}
-	return true, nil
 }
 
 func (r *UtxoPersisterReconciler) updateDeployment(dep *appsv1.Deployment, utxoPersister *teranodev1alpha1.UtxoPersister) error {
@@ -71,6 +59,7 @@ func defaultUtxoPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 			},
 			Spec: corev1.PodSpec{
 				ServiceAccountName: DefaultServiceAccountName,
+				SecurityContext:    defaultPodSecurityContext(),
 				Containers: []corev1.Container{
 					{
 						EnvFrom:         envFrom,
@@ -79,6 +68,7 @@ func defaultUtxoPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 						Image:           DefaultImage,
 						ImagePullPolicy: corev1.PullAlways,
 						Name:            UtxoPersisterName,
+						SecurityContext: defaultContainerSecurityContext(),
 						Resources: corev1.ResourceRequirements{
 							Limits: corev1.ResourceList{
 								corev1.ResourceMemory: resource.MustParse("2Gi"),
@@ -88,40 +78,9 @@ func defaultUtxoPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 								corev1.ResourceMemory: resource.MustParse("1Gi"),
 							},
 						},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/liveness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							InitialDelaySeconds: 1,
-							PeriodSeconds:       5,
-							FailureThreshold:    2,
-							TimeoutSeconds:      3,
-						},
-						StartupProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Path: "/health/readiness",
-									Port: intstr.FromInt32(HealthPort),
-								},
-							},
-							FailureThreshold: 30,
-							PeriodSeconds:    10,
-						},
+						ReadinessProbe: defaultReadinessProbe(HealthPort),
+						LivenessProbe:  defaultLivenessProbe(HealthPort),
+						StartupProbe:   defaultStartupProbe(HealthPort, SyncStartupWindow),
 						Ports: []corev1.ContainerPort{
 							{
 								ContainerPort: DebuggerPort,
@@ -137,6 +96,7 @@ func defaultUtxoPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 								MountPath: "/data",
 								Name:      SharedPVCName,
 							},
+							tmpVolumeMount(),
 						},
 					},
 				},
@@ -149,8 +109,21 @@ func defaultUtxoPersisterDeploymentSpec() *appsv1.DeploymentSpec {
 							},
 						},
 					},
+					tmpVolume(),
 				},
 			},
 		},
 	}
 }
+
+// utxoPersisterWorkload is the Deployment or StatefulSet of the UTXO persister service
+func utxoPersisterWorkload(up *teranodev1alpha1.UtxoPersister) workload {
+	return workload{
+		App:              UtxoPersisterName,
+		Kind:             up.Spec.WorkloadKind,
+		DataPath:         UtxoPersisterDataPath,
+		StorageClass:     up.Spec.StorageClass,
+		StorageResources: up.Spec.StorageResources,
+		DataSource:       up.Spec.StorageDataSource,
+	}
+}
@@ -39,6 +39,7 @@ func (r *UtxoPersisterReconciler) updateService(svc *corev1.Service, utxop *tera
 		return err
 	}
 	svc.Spec = *defaultUtxoPersisterServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, utxop)
 	return nil
 }
 
@@ -64,3 +65,15 @@ func defaultUtxoPersisterServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *UtxoPersisterReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	utxop := teranodev1alpha1.UtxoPersister{}
+	if err := r.Get(r.Context, r.NamespacedName, &utxop); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &utxop, "utxo-persister", "utxo-persister"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -39,6 +39,7 @@ func (r *ValidatorReconciler) updateService(svc *corev1.Service, validator *tera
 		return err
 	}
 	svc.Spec = *defaultValidatorServiceSpec()
+	utils.SetServiceOverrides(r.Client, svc, validator)
 	return nil
 }
 
@@ -57,8 +58,8 @@ func defaultValidatorServiceSpec() *corev1.ServiceSpec {
 		Ports: []corev1.ServicePort{
 			{
 				Name:       "validator-tcp",
-				Port:       int32(8081),
-				TargetPort: intstr.FromInt32(8081),
+				Port:       ValidatorGRPCPort,
+				TargetPort: intstr.FromInt32(ValidatorGRPCPort),
 				Protocol:   corev1.ProtocolTCP,
 			},
 			{
@@ -82,3 +83,15 @@ func defaultValidatorServiceSpec() *corev1.ServiceSpec {
 		},
 	}
 }
+
+// ReconcileIngresses exposes the service ports listed in the ingresses of the CR
+func (r *ValidatorReconciler) ReconcileIngresses(log logr.Logger) (bool, error) {
+	validator := teranodev1alpha1.Validator{}
+	if err := r.Get(r.Context, r.NamespacedName, &validator); err != nil {
+		return false, err
+	}
+	if err := reconcileServiceIngresses(r.Context, r.Client, r.Scheme, &validator, "validator", "validator"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrVolumeStatsNotFound is returned when no usage is reported for a PVC, such as when no running pod mounts it
+var ErrVolumeStatsNotFound = errors.New("no volume stats for the PVC")
+
+// VolumeStatsReader reads the bytes used on the volume of a PVC, and the capacity of its filesystem
+type VolumeStatsReader interface {
+	VolumeUsage(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (used int64, capacity int64, err error)
+}
+
+// KubeletVolumeStats reads the volume stats from the stats summary of the kubelet running a pod that mounts the PVC,
+// through the node proxy of the API server
+type KubeletVolumeStats struct {
+	Client client.Client
+	REST   rest.Interface
+}
+
+// kubeletSummary is the part of the kubelet stats summary holding the volume stats
+type kubeletSummary struct {
+	Pods []struct {
+		Volume []struct {
+			CapacityBytes *int64 `json:"capacityBytes"`
+			UsedBytes     *int64 `json:"usedBytes"`
+			PVCRef        *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// VolumeUsage implements VolumeStatsReader
+func (k *KubeletVolumeStats) VolumeUsage(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (int64, int64, error) {
+	node, err := k.nodeMounting(ctx, pvc)
+	if err != nil {
+		return 0, 0, err
+	}
+	body, err := k.REST.Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").DoRaw(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	summary := kubeletSummary{}
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return 0, 0, err
+	}
+	for _, pod := range summary.Pods {
+		for _, volume := range pod.Volume {
+			if volume.PVCRef == nil || volume.PVCRef.Name != pvc.Name || volume.PVCRef.Namespace != pvc.Namespace ||
+				volume.UsedBytes == nil || volume.CapacityBytes == nil {
+				continue
+			}
+			return *volume.UsedBytes, *volume.CapacityBytes, nil
+		}
+	}
+	return 0, 0, ErrVolumeStatsNotFound
+}
+
+// nodeMounting returns the node of a running pod mounting the PVC
+func (k *KubeletVolumeStats) nodeMounting(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	pods := &corev1.PodList{}
+	if err := k.Client.List(ctx, pods, client.InNamespace(pvc.Namespace)); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Spec.NodeName == "" {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvc.Name {
+				return pod.Spec.NodeName, nil
+			}
+		}
+	}
+	return "", ErrVolumeStatsNotFound
+}
+
+// PrometheusVolumeStats reads the kubelet_volume_stats metrics of a PVC from the query API of Prometheus
+type PrometheusVolumeStats struct {
+	URL    string
+	Client *http.Client
+}
+
+// prometheusResponse is the part of a Prometheus instant query response holding the samples
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Result []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// VolumeUsage implements VolumeStatsReader
+func (p *PrometheusVolumeStats) VolumeUsage(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (int64, int64, error) {
+	used, err := p.query(ctx, "kubelet_volume_stats_used_bytes", pvc)
+	if err != nil {
+		return 0, 0, err
+	}
+	capacity, err := p.query(ctx, "kubelet_volume_stats_capacity_bytes", pvc)
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, capacity, nil
+}
+
+func (p *PrometheusVolumeStats) query(ctx context.Context, metric string, pvc *corev1.PersistentVolumeClaim) (int64, error) {
+	query := fmt.Sprintf(`max(%s{namespace=%q,persistentvolumeclaim=%q})`, metric, pvc.Namespace, pvc.Name)
+	endpoint := strings.TrimSuffix(p.URL, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	httpClient := p.Client
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("prometheus query %s: %s", metric, resp.Status)
+	}
+	response := prometheusResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, err
+	}
+	if len(response.Data.Result) == 0 || len(response.Data.Result[0].Value) != 2 {
+		return 0, ErrVolumeStatsNotFound
+	}
+	value, ok := response.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, ErrVolumeStatsNotFound
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(f), nil
+}
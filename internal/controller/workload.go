@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// HeadlessServiceSuffix is appended to the app name for the service governing its StatefulSet
+const HeadlessServiceSuffix = "-headless"
+
+// StatefulSetClaimName is the name of the volume claim template of a StatefulSet
+const StatefulSetClaimName = "data"
+
+// workload is a stateful service that runs as a Deployment or a StatefulSet. The Deployment and the StatefulSet
+// are named after the app.
+type workload struct {
+	App              string
+	Kind             teranodev1alpha1.WorkloadKind
+	DataPath         string
+	StorageClass     string
+	StorageResources *corev1.ResourceRequirements
+	DataSource       *corev1.TypedLocalObjectReference
+}
+
+// statefulSet is true when the service runs as a StatefulSet
+func (w workload) statefulSet() bool {
+	return w.Kind == teranodev1alpha1.WorkloadKindStatefulSet
+}
+
+// dedicatedStorage is true when the service asks for storage other than the shared PVC
+func (w workload) dedicatedStorage() bool {
+	return w.StorageClass != "" || w.StorageResources != nil || w.DataSource != nil
+}
+
+// pvcSpec is the spec of the dedicated PVC of the service, or of its claim template
+func (w workload) pvcSpec() *corev1.PersistentVolumeClaimSpec {
+	spec := &corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{
+			corev1.ReadWriteOnce,
+		},
+		Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(DefaultServiceStorageSize),
+			},
+		},
+	}
+	if w.StorageClass != "" {
+		spec.StorageClassName = &w.StorageClass
+	}
+	if w.StorageResources != nil {
+		spec.Resources = corev1.VolumeResourceRequirements{
+			Limits:   w.StorageResources.Limits,
+			Requests: w.StorageResources.Requests,
+		}
+	}
+	spec.DataSource = w.DataSource
+	return spec
+}
+
+// statefulSetClaimName is the claim the StatefulSet creates for its first replica
+func (w workload) statefulSetClaimName() string {
+	return fmt.Sprintf("%s-%s-0", StatefulSetClaimName, w.App)
+}
+
+// reconcileWorkload renders the pods of a service with build, and runs them as a Deployment or a StatefulSet.
+// Switching kinds deletes the previous workload and moves its data claim to the new one.
+func reconcileWorkload(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner serviceOwner, w workload,
+	build func(dep *appsv1.Deployment) error) (bool, error) {
+	dep := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.App,
+			Namespace: owner.GetNamespace(),
+			Labels:    getAppLabels(w.App),
+		},
+	}
+	sts := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.App,
+			Namespace: owner.GetNamespace(),
+			Labels:    getAppLabels(w.App),
+		},
+	}
+	headless := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.App + HeadlessServiceSuffix,
+			Namespace: owner.GetNamespace(),
+			Labels:    getAppLabels(w.App),
+		},
+	}
+
+	if !w.statefulSet() {
+		if !w.dedicatedStorage() {
+			if err := checkStatefulSetClaim(ctx, c, owner.GetNamespace(), w); err != nil {
+				return false, err
+			}
+		}
+		if err := deleteOwned(ctx, c, owner, &sts); err != nil {
+			return false, err
+		}
+		if err := deleteOwned(ctx, c, owner, &headless); err != nil {
+			return false, err
+		}
+		if w.dedicatedStorage() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   servicePVCName(w.App),
+					Labels: getAppLabels(w.App),
+				},
+			}
+			if err := controllerutil.SetControllerReference(owner, pvc, scheme); err != nil {
+				return false, err
+			}
+			if err := migrateClaim(ctx, c, owner.GetNamespace(), w.App, w.statefulSetClaimName(), pvc); err != nil {
+				return false, err
+			}
+		}
+		if ok, err := reconcileServicePVC(ctx, c, scheme, owner, w); !ok || err != nil {
+			return ok, err
+		}
+		_, err := controllerutil.CreateOrUpdate(ctx, c, &dep, func() error {
+			if err := build(&dep); err != nil {
+				return err
+			}
+			if w.dedicatedStorage() {
+				mountServicePVC(&dep.Spec.Template.Spec, w.App, w.DataPath)
+			}
+			return nil
+		})
+		return err == nil, err
+	}
+
+	if err := deleteOwned(ctx, c, owner, &dep); err != nil {
+		return false, err
+	}
+	if w.dedicatedStorage() {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   w.statefulSetClaimName(),
+				Labels: getAppLabels(w.App),
+			},
+		}
+		if err := migrateClaim(ctx, c, owner.GetNamespace(), w.App, servicePVCName(w.App), pvc); err != nil {
+			return false, err
+		}
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, &headless, func() error {
+		if err := controllerutil.SetControllerReference(owner, &headless, scheme); err != nil {
+			return err
+		}
+		headless.Spec.ClusterIP = corev1.ClusterIPNone
+		headless.Spec.Selector = getAppLabels(w.App)
+		headless.Spec.PublishNotReadyAddresses = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// the pods are rendered as for a Deployment, then moved to the StatefulSet
+	rendered := dep.DeepCopy()
+	if err := build(rendered); err != nil {
+		return false, err
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, c, &sts, func() error {
+		if err := controllerutil.SetControllerReference(owner, &sts, scheme); err != nil {
+			return err
+		}
+		updateStatefulSet(&sts, rendered, w)
+		return nil
+	})
+	return err == nil, err
+}
+
+// checkStatefulSetClaim refuses to run a service without dedicated storage as a Deployment while the claim of the
+// first replica of its StatefulSet exists, since the Deployment only mounts the shared storage
+func checkStatefulSetClaim(ctx context.Context, c client.Client, namespace string, w workload) error {
+	claim := &corev1.PersistentVolumeClaim{}
+	err := c.Get(ctx, types.NamespacedName{Name: w.statefulSetClaimName(), Namespace: namespace}, claim)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !claim.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrStatefulSetClaim, claim.Name)
+}
+
+// updateStatefulSet sets the pods of the rendered Deployment on the StatefulSet. The selector, service name and
+// claim templates of a StatefulSet can't change, so they are only set when it's created.
+func updateStatefulSet(sts *appsv1.StatefulSet, dep *appsv1.Deployment, w workload) {
+	sts.Spec.Replicas = dep.Spec.Replicas
+	sts.Spec.MinReadySeconds = dep.Spec.MinReadySeconds
+	sts.Spec.RevisionHistoryLimit = dep.Spec.RevisionHistoryLimit
+	sts.Spec.Template = dep.Spec.Template
+	// one pod at a time, deleted before its replacement is created, so that the replicas never write at once
+	// and a host exposure never has two pods binding the same ports on a node
+	sts.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+	}
+	if sts.CreationTimestamp.IsZero() {
+		sts.Spec.Selector = dep.Spec.Selector
+		sts.Spec.ServiceName = w.App + HeadlessServiceSuffix
+		sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   StatefulSetClaimName,
+					Labels: getAppLabels(w.App),
+				},
+				Spec: *w.pvcSpec(),
+			},
+		}
+	}
+	if len(sts.Spec.Template.Spec.Containers) > 0 {
+		container := &sts.Spec.Template.Spec.Containers[0]
+		setVolumeMount(container, corev1.VolumeMount{
+			Name:      StatefulSetClaimName,
+			MountPath: w.DataPath,
+		})
+	}
+}
+
+// deleteOwned deletes the object when it exists and is controlled by the owner
+func deleteOwned(ctx context.Context, c client.Client, owner serviceOwner, obj client.Object) error {
+	err := c.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, obj)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(obj, owner) {
+		return nil
+	}
+	return client.IgnoreNotFound(c.Delete(ctx, obj))
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	teranodev1alpha1 "github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Workload kinds", func() {
+	ctx := context.Background()
+
+	It("should run the blockchain as a StatefulSet", func() {
+		cluster := newTestCluster(ctx, func(cluster *teranodev1alpha1.Cluster) {
+			cluster.Spec.Blockchain.Spec = &teranodev1alpha1.BlockchainSpec{
+				WorkloadKind: teranodev1alpha1.WorkloadKindStatefulSet,
+				StorageClass: "gp3",
+			}
+		})
+		reconcileTestCluster(ctx, cluster)
+		blockchainReconciler := &BlockchainReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconcileTestService(ctx, blockchainReconciler, cluster, "blockchain")
+		Expect(err).NotTo(HaveOccurred())
+
+		sts := &appsv1.StatefulSet{}
+		Expect(getTestObject(ctx, cluster, "blockchain", sts)).To(Succeed())
+		Expect(sts.Spec.ServiceName).To(Equal("blockchain" + HeadlessServiceSuffix))
+		Expect(sts.Spec.PodManagementPolicy).To(Equal(appsv1.OrderedReadyPodManagement))
+		Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+		Expect(*sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal("gp3"))
+		Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      StatefulSetClaimName,
+			MountPath: BlockchainDataPath,
+		}))
+		headless := &v1.Service{}
+		Expect(getTestObject(ctx, cluster, "blockchain"+HeadlessServiceSuffix, headless)).To(Succeed())
+		Expect(headless.Spec.ClusterIP).To(Equal(v1.ClusterIPNone))
+		err = getTestObject(ctx, cluster, "blockchain", &appsv1.Deployment{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// switching back without dedicated storage is refused while the claim of the first replica exists
+		claim := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-blockchain-0", StatefulSetClaimName),
+				Namespace: cluster.Namespace,
+			},
+			Spec: sts.Spec.VolumeClaimTemplates[0].Spec,
+		}
+		Expect(k8sClient.Create(ctx, claim)).To(Succeed())
+		Expect(updateTestObject(ctx, cluster, func() {
+			cluster.Spec.Blockchain.Spec = nil
+		})).To(Succeed())
+		reconcileTestCluster(ctx, cluster)
+		_, err = reconcileTestService(ctx, blockchainReconciler, cluster, "blockchain")
+		Expect(err).To(MatchError(ErrStatefulSetClaim))
+
+		// once the claim is deleted, it runs a Deployment again
+		Expect(k8sClient.Delete(ctx, claim)).To(Succeed())
+		_, err = reconcileTestService(ctx, blockchainReconciler, cluster, "blockchain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTestObject(ctx, cluster, "blockchain", &appsv1.Deployment{})).To(Succeed())
+		err = getTestObject(ctx, cluster, "blockchain", sts)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should restore the reclaim policy of a moved volume once its new claim is bound", func() {
+		cluster := newTestCluster(ctx, nil)
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: cluster.Namespace + "-blockchain"},
+			Spec: v1.PersistentVolumeSpec{
+				Capacity:                      v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				AccessModes:                   []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete,
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					HostPath: &v1.HostPathVolumeSource{Path: "/data"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pv)).To(Succeed())
+		old := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: servicePVCName("blockchain"), Namespace: cluster.Namespace},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				Resources: v1.VolumeResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+				VolumeName: pv.Name,
+			},
+		}
+		Expect(k8sClient.Create(ctx, old)).To(Succeed())
+
+		// the volume is retained while it moves
+		to := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: StatefulSetClaimName + "-blockchain-0"}}
+		Expect(migrateClaim(ctx, k8sClient, cluster.Namespace, "blockchain", old.Name, to)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).To(Succeed())
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimRetain))
+		Expect(pv.Annotations).To(HaveKeyWithValue(ReclaimPolicyAnnotation, string(v1.PersistentVolumeReclaimDelete)))
+		Expect(pv.Spec.ClaimRef.Name).To(Equal(to.Name))
+
+		// and keeps it until the new claim is bound
+		to = &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: StatefulSetClaimName + "-blockchain-0"}}
+		Expect(migrateClaim(ctx, k8sClient, cluster.Namespace, "blockchain", old.Name, to)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).To(Succeed())
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimRetain))
+
+		claim := &v1.PersistentVolumeClaim{}
+		Expect(getTestObject(ctx, cluster, to.Name, claim)).To(Succeed())
+		claim.Status.Phase = v1.ClaimBound
+		Expect(k8sClient.Status().Update(ctx, claim)).To(Succeed())
+		Expect(migrateClaim(ctx, k8sClient, cluster.Namespace, "blockchain", old.Name, to)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).To(Succeed())
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(v1.PersistentVolumeReclaimDelete))
+		Expect(pv.Annotations).NotTo(HaveKey(ReclaimPolicyAnnotation))
+	})
+})
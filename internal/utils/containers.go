@@ -0,0 +1,59 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// SetInitContainers adds the init containers to the pod spec, replacing any with the same name
+func SetInitContainers(podSpec *corev1.PodSpec, containers []corev1.Container) {
+	for _, container := range containers {
+		podSpec.InitContainers = upsertContainer(podSpec.InitContainers, *container.DeepCopy())
+	}
+}
+
+// SetSidecarContainers adds the sidecars to the pod spec as native sidecars, i.e. init containers
+// that keep running for the lifetime of the pod
+func SetSidecarContainers(podSpec *corev1.PodSpec, sidecars []v1alpha1.SidecarContainer) {
+	for _, sidecar := range sidecars {
+		container := *sidecar.Container.DeepCopy()
+		container.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		podSpec.InitContainers = upsertContainer(podSpec.InitContainers, container)
+	}
+	InheritSidecarEnv(podSpec, sidecars)
+}
+
+// InheritSidecarEnv prepends the env and envFrom of the service container to the sidecars that ask for it.
+// The sidecar env is rebuilt from its spec every time, so this can be called again once the cluster env is set.
+func InheritSidecarEnv(podSpec *corev1.PodSpec, sidecars []v1alpha1.SidecarContainer) {
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+	main := podSpec.Containers[0]
+	for _, sidecar := range sidecars {
+		if !sidecar.InheritEnv {
+			continue
+		}
+		for i := range podSpec.InitContainers {
+			if podSpec.InitContainers[i].Name != sidecar.Name {
+				continue
+			}
+			env := append([]corev1.EnvVar{}, main.Env...)
+			podSpec.InitContainers[i].Env = append(env, sidecar.Env...)
+			envFrom := append([]corev1.EnvFromSource{}, main.EnvFrom...)
+			podSpec.InitContainers[i].EnvFrom = append(envFrom, sidecar.EnvFrom...)
+		}
+	}
+}
+
+func upsertContainer(containers []corev1.Container, container corev1.Container) []corev1.Container {
+	for i := range containers {
+		if containers[i].Name == container.Name {
+			containers[i] = container
+			return containers
+		}
+	}
+	return append(containers, container)
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Containers", func() {
+	It("should replace the init containers with the same name", func() {
+		podSpec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "wait-for-kafka", Image: "busybox:1"}},
+		}
+		containers := []corev1.Container{
+			{Name: "wait-for-kafka", Image: "busybox:2"},
+			{Name: "migrate", Image: "migrate:1"},
+		}
+		SetInitContainers(podSpec, containers)
+		SetInitContainers(podSpec, containers)
+
+		Expect(podSpec.InitContainers).To(Equal(containers))
+	})
+
+	It("should run the sidecars as native sidecars with the env of the service", func() {
+		podSpec := &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Env:  []corev1.EnvVar{{Name: "network", Value: "mainnet"}},
+					EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cluster-config"}}},
+					},
+				},
+			},
+		}
+		sidecars := []v1alpha1.SidecarContainer{
+			{
+				Container: corev1.Container{
+					Name: "exporter",
+					Env:  []corev1.EnvVar{{Name: "port", Value: "9100"}},
+				},
+				InheritEnv: true,
+			},
+			{
+				Container: corev1.Container{Name: "shipper"},
+			},
+		}
+		SetSidecarContainers(podSpec, sidecars)
+
+		Expect(podSpec.InitContainers).To(HaveLen(2))
+		for _, container := range podSpec.InitContainers {
+			Expect(container.RestartPolicy).To(Equal(ptr.To(corev1.ContainerRestartPolicyAlways)))
+		}
+		Expect(sidecars[0].RestartPolicy).To(BeNil())
+		Expect(podSpec.InitContainers[0].Env).To(Equal([]corev1.EnvVar{
+			{Name: "network", Value: "mainnet"},
+			{Name: "port", Value: "9100"},
+		}))
+		Expect(podSpec.InitContainers[0].EnvFrom).To(Equal(podSpec.Containers[0].EnvFrom))
+		Expect(podSpec.InitContainers[1].Env).To(BeEmpty())
+
+		// the env is rebuilt, not appended to, when it's inherited again
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{Name: "clientName", Value: "node"})
+		InheritSidecarEnv(podSpec, sidecars)
+		Expect(podSpec.InitContainers[0].Env).To(Equal([]corev1.EnvVar{
+			{Name: "network", Value: "mainnet"},
+			{Name: "clientName", Value: "node"},
+			{Name: "port", Value: "9100"},
+		}))
+	})
+})
@@ -53,6 +53,21 @@ func SetDeploymentOverridesWithContext(ctx context.Context, log logr.Logger, cli
 		dep.Spec.Template.Spec.Affinity = cr.DeploymentOverrides().Affinity
 	}
 
+	// If user configures taints, tolerate them
+	if cr.DeploymentOverrides().Taints != nil {
+		AppendTolerations(&dep.Spec.Template.Spec, TolerationsForTaints(*cr.DeploymentOverrides().Taints))
+	}
+
+	// If user configures pod anti-affinity, merge it with the default anti-affinity
+	if cr.DeploymentOverrides().PodAntiAffinity != nil {
+		MergePodAntiAffinity(&dep.Spec.Template.Spec, cr.DeploymentOverrides().PodAntiAffinity)
+	}
+
+	// If user configures a node pool
+	if cr.DeploymentOverrides().NodePool != "" {
+		SetNodePool(&dep.Spec.Template, cr.DeploymentOverrides().NodePool)
+	}
+
 	// if user configures resources requests
 	if cr.DeploymentOverrides().Resources != nil {
 		dep.Spec.Template.Spec.Containers[0].Resources = *cr.DeploymentOverrides().Resources
@@ -71,6 +86,23 @@ func SetDeploymentOverridesWithContext(ctx context.Context, log logr.Logger, cli
 		dep.Spec.Template.Spec.ServiceAccountName = cr.DeploymentOverrides().ServiceAccount
 	}
 
+	// if user configures service account token mounting or security contexts
+	if cr.DeploymentOverrides().AutomountServiceAccountToken != nil {
+		dep.Spec.Template.Spec.AutomountServiceAccountToken = cr.DeploymentOverrides().AutomountServiceAccountToken
+	}
+	if cr.DeploymentOverrides().PodSecurityContext != nil {
+		dep.Spec.Template.Spec.SecurityContext = cr.DeploymentOverrides().PodSecurityContext
+	}
+	if cr.DeploymentOverrides().SecurityContext != nil {
+		dep.Spec.Template.Spec.Containers[0].SecurityContext = cr.DeploymentOverrides().SecurityContext
+	}
+
+	// if user configures probes
+	container := &dep.Spec.Template.Spec.Containers[0]
+	container.LivenessProbe = OverrideProbe(container.LivenessProbe, cr.DeploymentOverrides().LivenessProbe)
+	container.ReadinessProbe = OverrideProbe(container.ReadinessProbe, cr.DeploymentOverrides().ReadinessProbe)
+	container.StartupProbe = OverrideProbe(container.StartupProbe, cr.DeploymentOverrides().StartupProbe)
+
 	// if user configures env vars
 	if len(cr.DeploymentOverrides().Env) > 0 {
 		dep.Spec.Template.Spec.Containers[0].Env = append(dep.Spec.Template.Spec.Containers[0].Env, cr.DeploymentOverrides().Env...)
@@ -117,9 +149,24 @@ func SetDeploymentOverridesWithContext(ctx context.Context, log logr.Logger, cli
 	if len(cr.DeploymentOverrides().VolumeMounts) > 0 {
 		dep.Spec.Template.Spec.Containers[0].VolumeMounts = append(dep.Spec.Template.Spec.Containers[0].VolumeMounts, cr.DeploymentOverrides().VolumeMounts...)
 	}
+	// if user configures scratch space
+	if err := SetScratch(ctx, client, &dep.Spec.Template, cr.DeploymentOverrides().Scratch); err != nil {
+		log.Error(err, "unable to constrain the pods to the nodes with local PVs")
+	}
 	if cr.DeploymentOverrides().Replicas != nil {
 		dep.Spec.Replicas = cr.DeploymentOverrides().Replicas
 	}
+
+	// if user configures pod template labels or annotations
+	SetPodTemplateMetadata(dep, cr.DeploymentOverrides().PodLabels, cr.DeploymentOverrides().PodAnnotations, true)
+
+	// if user configures init containers or sidecars
+	if len(cr.DeploymentOverrides().InitContainers) > 0 {
+		SetInitContainers(&dep.Spec.Template.Spec, cr.DeploymentOverrides().InitContainers)
+	}
+	if len(cr.DeploymentOverrides().SidecarContainers) > 0 {
+		SetSidecarContainers(&dep.Spec.Template.Spec, cr.DeploymentOverrides().SidecarContainers)
+	}
 }
 
 //nolint:gocognit,gocyclo // Function complexity is inherent to handling multiple override cases
@@ -157,8 +204,9 @@ func SetClusterOverrides(client client.Client, dep *appsv1.Deployment, cr v1alph
 	if clusterOwner.Spec.Image != "" {
 		dep.Spec.Template.Spec.Containers[0].Image = clusterOwner.Spec.Image
 	}
-	if len(clusterOwner.Spec.EnvFrom) == 0 {
-		return
+	// The cluster node pool applies to every service that doesn't set its own
+	if clusterOwner.Spec.NodePool != "" && (cr.DeploymentOverrides() == nil || cr.DeploymentOverrides().NodePool == "") {
+		SetNodePool(&dep.Spec.Template, clusterOwner.Spec.NodePool)
 	}
 
 	// Append cluster envFrom vars, avoiding duplicates
@@ -180,6 +228,46 @@ func SetClusterOverrides(client client.Client, dep *appsv1.Deployment, cr v1alph
 			dep.Spec.Template.Spec.Containers[0].EnvFrom = append(dep.Spec.Template.Spec.Containers[0].EnvFrom, clusterEnvFrom)
 		}
 	}
+
+	// Service pod labels and annotations take precedence over the cluster ones
+	SetPodTemplateMetadata(dep, clusterOwner.Spec.PodLabels, clusterOwner.Spec.PodAnnotations, false)
+	SetInitContainers(&dep.Spec.Template.Spec, clusterOwner.Spec.InitContainers)
+	SetSidecarContainers(&dep.Spec.Template.Spec, clusterOwner.Spec.SidecarContainers)
+	// Service sidecars were added before the cluster env was set, so let them inherit it now
+	if cr.DeploymentOverrides() != nil {
+		InheritSidecarEnv(&dep.Spec.Template.Spec, cr.DeploymentOverrides().SidecarContainers)
+	}
+	SetInternalTLS(&dep.Spec.Template, clusterOwner, cr)
+	SetStoreVolumes(&dep.Spec.Template, clusterOwner)
+}
+
+// SetPodTemplateMetadata merges labels and annotations onto the pod template. Labels used by the
+// deployment selector are never changed, and existing keys are only replaced when overwrite is set.
+func SetPodTemplateMetadata(dep *appsv1.Deployment, labels, annotations map[string]string, overwrite bool) {
+	template := &dep.Spec.Template
+	for k, v := range labels {
+		if dep.Spec.Selector != nil {
+			if _, ok := dep.Spec.Selector.MatchLabels[k]; ok {
+				continue
+			}
+		}
+		if template.Labels == nil {
+			template.Labels = map[string]string{}
+		}
+		if _, ok := template.Labels[k]; ok && !overwrite {
+			continue
+		}
+		template.Labels[k] = v
+	}
+	for k, v := range annotations {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		if _, ok := template.Annotations[k]; ok && !overwrite {
+			continue
+		}
+		template.Annotations[k] = v
+	}
 }
 
 // ScaleStatus defines the interface for CRs that support scale subresource
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Pod template metadata", func() {
+	It("should never change the selector labels", func() {
+		dep := &appsv1.Deployment{}
+		dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "asset"}}
+		dep.Spec.Template.Labels = map[string]string{"app": "asset", "tier": "frontend"}
+		dep.Spec.Template.Annotations = map[string]string{"checksum": "old"}
+
+		SetPodTemplateMetadata(dep,
+			map[string]string{"app": "other", "tier": "backend", "team": "node"},
+			map[string]string{"checksum": "new", "scrape": "true"},
+			false)
+		Expect(dep.Spec.Template.Labels).To(Equal(map[string]string{"app": "asset", "tier": "frontend", "team": "node"}))
+		Expect(dep.Spec.Template.Annotations).To(Equal(map[string]string{"checksum": "old", "scrape": "true"}))
+
+		SetPodTemplateMetadata(dep,
+			map[string]string{"app": "other", "tier": "backend"},
+			map[string]string{"checksum": "new"},
+			true)
+		Expect(dep.Spec.Template.Labels).To(Equal(map[string]string{"app": "asset", "tier": "backend", "team": "node"}))
+		Expect(dep.Spec.Template.Annotations).To(HaveKeyWithValue("checksum", "new"))
+	})
+})
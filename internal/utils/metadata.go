@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The annotation and label keys taken from the spec on the last reconcile, so that the keys removed from the
+// spec are removed from the object while the keys set by the operator or by others are left alone
+const (
+	ManagedAnnotationsAnnotation = "teranode.bsvblockchain.org/managed-annotations"
+	ManagedLabelsAnnotation      = "teranode.bsvblockchain.org/managed-labels"
+)
+
+// ReplaceAnnotations replaces the annotations taken from the spec on the last reconcile with the given ones
+func ReplaceAnnotations(obj metav1.Object, values map[string]string) {
+	annotations := replaceManagedKeys(obj.GetAnnotations(), obj.GetAnnotations()[ManagedAnnotationsAnnotation], values)
+	obj.SetAnnotations(recordManagedKeys(annotations, ManagedAnnotationsAnnotation, values))
+}
+
+// ReplaceLabels replaces the labels taken from the spec on the last reconcile with the given ones
+func ReplaceLabels(obj metav1.Object, values map[string]string) {
+	obj.SetLabels(replaceManagedKeys(obj.GetLabels(), obj.GetAnnotations()[ManagedLabelsAnnotation], values))
+	obj.SetAnnotations(recordManagedKeys(obj.GetAnnotations(), ManagedLabelsAnnotation, values))
+}
+
+func replaceManagedKeys(target map[string]string, managed string, values map[string]string) map[string]string {
+	for _, k := range strings.Split(managed, ",") {
+		delete(target, k)
+	}
+	return mergeStringMaps(target, values)
+}
+
+func recordManagedKeys(annotations map[string]string, key string, values map[string]string) map[string]string {
+	if len(values) == 0 {
+		delete(annotations, key)
+		return annotations
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return mergeStringMaps(annotations, map[string]string{key: strings.Join(keys, ",")})
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Metadata", func() {
+	It("should remove the annotations dropped from the spec and keep the others", func() {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{"cloud.example.com/lb-id": "set-by-the-cloud"},
+			},
+		}
+		ReplaceAnnotations(svc, map[string]string{"a": "1", "b": "2"})
+		Expect(svc.Annotations).To(Equal(map[string]string{
+			"cloud.example.com/lb-id":    "set-by-the-cloud",
+			"a":                          "1",
+			"b":                          "2",
+			ManagedAnnotationsAnnotation: "a,b",
+		}))
+
+		ReplaceAnnotations(svc, map[string]string{"b": "3"})
+		Expect(svc.Annotations).To(Equal(map[string]string{
+			"cloud.example.com/lb-id":    "set-by-the-cloud",
+			"b":                          "3",
+			ManagedAnnotationsAnnotation: "b",
+		}))
+
+		ReplaceAnnotations(svc, nil)
+		Expect(svc.Annotations).To(Equal(map[string]string{
+			"cloud.example.com/lb-id": "set-by-the-cloud",
+		}))
+	})
+
+	It("should remove the labels dropped from the spec and keep the others", func() {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "asset"},
+			},
+		}
+		ReplaceLabels(svc, map[string]string{"team": "node"})
+		Expect(svc.Labels).To(Equal(map[string]string{"app": "asset", "team": "node"}))
+		Expect(svc.Annotations).To(HaveKeyWithValue(ManagedLabelsAnnotation, "team"))
+
+		ReplaceLabels(svc, nil)
+		Expect(svc.Labels).To(Equal(map[string]string{"app": "asset"}))
+		Expect(svc.Annotations).NotTo(HaveKey(ManagedLabelsAnnotation))
+	})
+})
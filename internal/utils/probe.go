@@ -0,0 +1,47 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// OverrideProbe applies a probe override to a default probe. A disabled override removes the probe,
+// an override with a handler replaces it and an override without one only replaces the timings that are set.
+func OverrideProbe(probe *corev1.Probe, override *v1alpha1.ProbeOverride) *corev1.Probe {
+	if override == nil {
+		return probe
+	}
+	if override.Disabled {
+		return nil
+	}
+	hasHandler := override.Exec != nil || override.HTTPGet != nil || override.TCPSocket != nil || override.GRPC != nil
+	if hasHandler {
+		return override.Probe.DeepCopy()
+	}
+	// Timings without a handler have nothing to apply to
+	if probe == nil {
+		return nil
+	}
+
+	result := probe.DeepCopy()
+	if override.InitialDelaySeconds != 0 {
+		result.InitialDelaySeconds = override.InitialDelaySeconds
+	}
+	if override.TimeoutSeconds != 0 {
+		result.TimeoutSeconds = override.TimeoutSeconds
+	}
+	if override.PeriodSeconds != 0 {
+		result.PeriodSeconds = override.PeriodSeconds
+	}
+	if override.SuccessThreshold != 0 {
+		result.SuccessThreshold = override.SuccessThreshold
+	}
+	if override.FailureThreshold != 0 {
+		result.FailureThreshold = override.FailureThreshold
+	}
+	if override.TerminationGracePeriodSeconds != nil {
+		result.TerminationGracePeriodSeconds = override.TerminationGracePeriodSeconds
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// HostnameTopologyKey is the topology key used to spread pods across nodes
+const HostnameTopologyKey = "kubernetes.io/hostname"
+
+// SetNodePool dedicates the pod template to a node pool. Pods tolerate the pool taint,
+// require nodes labeled with the pool name and prefer not to share a node with another replica.
+func SetNodePool(template *corev1.PodTemplateSpec, pool string) {
+	if pool == "" {
+		return
+	}
+	AppendTolerations(&template.Spec, []corev1.Toleration{
+		{
+			Key:      v1alpha1.NodePoolLabel,
+			Operator: corev1.TolerationOpEqual,
+			Value:    pool,
+			Effect:   corev1.TaintEffectNoSchedule,
+		},
+	})
+
+	requireNodeSelectorRequirement(&template.Spec, corev1.NodeSelectorRequirement{
+		Key:      v1alpha1.NodePoolLabel,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{pool},
+	})
+
+	app, ok := template.Labels["app"]
+	if !ok {
+		return
+	}
+	MergePodAntiAffinity(&template.Spec, &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app": app,
+						},
+					},
+					TopologyKey: HostnameTopologyKey,
+				},
+				Weight: 100,
+			},
+		},
+	})
+}
+
+// requireNodeSelectorRequirement adds a required node affinity to the pod
+func requireNodeSelectorRequirement(podSpec *corev1.PodSpec, requirement corev1.NodeSelectorRequirement) {
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+		len(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{}},
+		}
+	}
+	// Node selector terms are ORed, so the requirement has to be part of every term
+	terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		if !containsNodeSelectorRequirement(terms[i].MatchExpressions, requirement) {
+			terms[i].MatchExpressions = append(terms[i].MatchExpressions, requirement)
+		}
+	}
+}
+
+// TolerationsForTaints returns the tolerations needed to schedule onto nodes with the given taints
+func TolerationsForTaints(taints []corev1.Taint) []corev1.Toleration {
+	tolerations := make([]corev1.Toleration, 0, len(taints))
+	for _, taint := range taints {
+		toleration := corev1.Toleration{
+			Key:      taint.Key,
+			Operator: corev1.TolerationOpEqual,
+			Value:    taint.Value,
+			Effect:   taint.Effect,
+		}
+		if taint.Value == "" {
+			toleration.Operator = corev1.TolerationOpExists
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations
+}
+
+// AppendTolerations adds the tolerations the pod spec doesn't already have
+func AppendTolerations(podSpec *corev1.PodSpec, tolerations []corev1.Toleration) {
+	for _, toleration := range tolerations {
+		found := false
+		for _, existing := range podSpec.Tolerations {
+			if existing.MatchToleration(&toleration) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			podSpec.Tolerations = append(podSpec.Tolerations, toleration)
+		}
+	}
+}
+
+// MergePodAntiAffinity adds the anti-affinity terms to the ones already on the pod spec
+func MergePodAntiAffinity(podSpec *corev1.PodSpec, antiAffinity *corev1.PodAntiAffinity) {
+	if antiAffinity == nil {
+		return
+	}
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	target := podSpec.Affinity.PodAntiAffinity
+	for _, term := range antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		found := false
+		for _, existing := range target.RequiredDuringSchedulingIgnoredDuringExecution {
+			if equality.Semantic.DeepEqual(existing, term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			target.RequiredDuringSchedulingIgnoredDuringExecution = append(target.RequiredDuringSchedulingIgnoredDuringExecution, term)
+		}
+	}
+	for _, term := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		found := false
+		for _, existing := range target.PreferredDuringSchedulingIgnoredDuringExecution {
+			if equality.Semantic.DeepEqual(existing.PodAffinityTerm, term.PodAffinityTerm) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			target.PreferredDuringSchedulingIgnoredDuringExecution = append(target.PreferredDuringSchedulingIgnoredDuringExecution, term)
+		}
+	}
+}
+
+func containsNodeSelectorRequirement(requirements []corev1.NodeSelectorRequirement, requirement corev1.NodeSelectorRequirement) bool {
+	for _, existing := range requirements {
+		if equality.Semantic.DeepEqual(existing, requirement) {
+			return true
+		}
+	}
+	return false
+}
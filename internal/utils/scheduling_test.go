@@ -0,0 +1,97 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Scheduling", func() {
+	It("should dedicate the pods to the node pool in every node selector term", func() {
+		template := &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "block-validator"}},
+			Spec: corev1.PodSpec{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+								{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}},
+							},
+						},
+					},
+				},
+			},
+		}
+		SetNodePool(template, "validators")
+		SetNodePool(template, "validators")
+
+		poolRequirement := corev1.NodeSelectorRequirement{
+			Key:      v1alpha1.NodePoolLabel,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"validators"},
+		}
+		terms := template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(2))
+		for _, term := range terms {
+			Expect(term.MatchExpressions).To(HaveLen(2))
+			Expect(term.MatchExpressions).To(ContainElement(poolRequirement))
+		}
+		Expect(template.Spec.Tolerations).To(ConsistOf(corev1.Toleration{
+			Key:      v1alpha1.NodePoolLabel,
+			Operator: corev1.TolerationOpEqual,
+			Value:    "validators",
+			Effect:   corev1.TaintEffectNoSchedule,
+		}))
+		preferred := template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		Expect(preferred).To(HaveLen(1))
+		Expect(preferred[0].PodAffinityTerm.LabelSelector.MatchLabels).To(HaveKeyWithValue("app", "block-validator"))
+	})
+
+	It("should tolerate taints with and without a value", func() {
+		Expect(TolerationsForTaints([]corev1.Taint{
+			{Key: "dedicated", Value: "teranode", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "gpu", Effect: corev1.TaintEffectNoExecute},
+		})).To(Equal([]corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "teranode", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+		}))
+	})
+
+	It("should merge the anti-affinity terms instead of replacing them", func() {
+		term := func(app string) corev1.PodAffinityTerm {
+			return corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+				TopologyKey:   HostnameTopologyKey,
+			}
+		}
+		podSpec := &corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term("asset")},
+				},
+			},
+		}
+		MergePodAntiAffinity(podSpec, &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term("asset"), term("propagation")},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 50, PodAffinityTerm: term("asset")},
+			},
+		})
+		// a preferred term with another weight is the same term
+		MergePodAntiAffinity(podSpec, &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 100, PodAffinityTerm: term("asset")},
+			},
+		})
+
+		antiAffinity := podSpec.Affinity.PodAntiAffinity
+		Expect(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.PodAffinityTerm{term("asset"), term("propagation")}))
+		Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.WeightedPodAffinityTerm{
+			{Weight: 50, PodAffinityTerm: term("asset")},
+		}))
+	})
+})
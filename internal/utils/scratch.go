@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// Scratch space of the services
+const (
+	DefaultScratchMountPath = "/scratch"
+	// DefaultScratchSetting points to the scratch space when no setting is set and the service has no cache
+	// settings of its own. It's distinct from the store settings, which point to the store volumes.
+	DefaultScratchSetting = "scratch_dir"
+	scratchVolumeName     = "scratch"
+)
+
+// ScratchCacheSettings are the cache settings of the services, pointed to the scratch space when no setting is set
+var ScratchCacheSettings = map[string][]string{
+	"block-validator":   {"blockvalidation_cache_dir"},
+	"subtree-validator": {"subtreevalidation_cache_dir"},
+}
+
+// SetScratch mounts the scratch space of the service and points its settings to it. With local PVs, the pods
+// are constrained to the nodes that have PVs of the storage class.
+func SetScratch(ctx context.Context, c client.Client, template *corev1.PodTemplateSpec, def *v1alpha1.ScratchDef) error {
+	podSpec := &template.Spec
+	if def == nil || len(podSpec.Containers) == 0 {
+		return nil
+	}
+	mountPath := def.MountPath
+	if mountPath == "" {
+		mountPath = DefaultScratchMountPath
+	}
+	volume := corev1.Volume{Name: scratchVolumeName}
+	switch {
+	case def.Local != nil:
+		volume.Ephemeral = scratchEphemeral(def.Local)
+		hosts, err := localPVHosts(ctx, c, def.Local.StorageClass)
+		if err != nil {
+			return err
+		}
+		if len(hosts) > 0 {
+			requireNodeSelectorRequirement(podSpec, corev1.NodeSelectorRequirement{
+				Key:      HostnameTopologyKey,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   hosts,
+			})
+		}
+	case def.Ephemeral != nil:
+		volume.Ephemeral = scratchEphemeral(def.Ephemeral)
+	case def.EmptyDir != nil:
+		volume.EmptyDir = def.EmptyDir.DeepCopy()
+	default:
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      scratchVolumeName,
+		MountPath: mountPath,
+	})
+	settings := def.Settings
+	if len(settings) == 0 && len(def.URLSettings) == 0 {
+		settings = ScratchCacheSettings[template.Labels["app"]]
+		if len(settings) == 0 {
+			settings = []string{DefaultScratchSetting}
+		}
+	}
+	for _, setting := range settings {
+		if !hasEnv(container, setting) {
+			container.Env = append(container.Env, corev1.EnvVar{Name: setting, Value: mountPath})
+		}
+	}
+	for _, setting := range def.URLSettings {
+		if !hasEnv(container, setting) {
+			container.Env = append(container.Env, corev1.EnvVar{Name: setting, Value: "file://" + mountPath})
+		}
+	}
+	return nil
+}
+
+// scratchEphemeral is a generic ephemeral volume, provisioned for every pod
+func scratchEphemeral(def *v1alpha1.ScratchVolume) *corev1.EphemeralVolumeSource {
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: def.Size.DeepCopy(),
+			},
+		},
+	}
+	if def.StorageClass != "" {
+		spec.StorageClassName = &def.StorageClass
+	}
+	return &corev1.EphemeralVolumeSource{
+		VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1alpha1.TeranodeLabel: "true"},
+			},
+			Spec: spec,
+		},
+	}
+}
+
+// localPVHosts are the nodes the local PVs of the storage class are on, sorted so that the pod template is stable
+func localPVHosts(ctx context.Context, c client.Client, storageClass string) ([]string, error) {
+	pvs := &corev1.PersistentVolumeList{}
+	if err := c.List(ctx, pvs); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	hosts := []string{}
+	for _, pv := range pvs.Items {
+		if pv.Spec.StorageClassName != storageClass || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+		for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+			for _, expression := range term.MatchExpressions {
+				if expression.Key != HostnameTopologyKey || expression.Operator != corev1.NodeSelectorOpIn {
+					continue
+				}
+				for _, host := range expression.Values {
+					if !seen[host] {
+						seen[host] = true
+						hosts = append(hosts, host)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(hosts)
+	return hosts, nil
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+var _ = Describe("Scratch space", func() {
+	ctx := context.Background()
+
+	template := func(app string) *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": app}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}},
+			},
+		}
+	}
+
+	It("should point the cache settings of the service to the scratch space", func() {
+		c := fake.NewClientBuilder().Build()
+
+		validator := template("block-validator")
+		Expect(SetScratch(ctx, c, validator, &v1alpha1.ScratchDef{})).To(Succeed())
+		Expect(validator.Spec.Volumes).To(ConsistOf(corev1.Volume{
+			Name:         scratchVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}))
+		Expect(validator.Spec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+			Name:      scratchVolumeName,
+			MountPath: DefaultScratchMountPath,
+		}))
+		Expect(validator.Spec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{
+			Name:  ScratchCacheSettings["block-validator"][0],
+			Value: DefaultScratchMountPath,
+		}))
+
+		// a service without cache settings gets the generic one, and settings already set are kept
+		asset := template("asset")
+		asset.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "blockstore", Value: "file:///data/blockstore"}}
+		Expect(SetScratch(ctx, c, asset, &v1alpha1.ScratchDef{
+			MountPath:   "/tmp/scratch",
+			URLSettings: []string{"blockstore", "temp_store"},
+		})).To(Succeed())
+		Expect(asset.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: "blockstore", Value: "file:///data/blockstore"},
+			corev1.EnvVar{Name: "temp_store", Value: "file:///tmp/scratch"},
+		))
+		asset = template("asset")
+		Expect(SetScratch(ctx, c, asset, &v1alpha1.ScratchDef{})).To(Succeed())
+		Expect(asset.Spec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: DefaultScratchSetting, Value: DefaultScratchMountPath}))
+	})
+
+	It("should schedule the pods on the nodes with local PVs of the storage class", func() {
+		localPV := func(name, storageClass, host string) *corev1.PersistentVolume {
+			return &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: storageClass,
+					NodeAffinity: &corev1.VolumeNodeAffinity{
+						Required: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{MatchExpressions: []corev1.NodeSelectorRequirement{
+									{Key: HostnameTopologyKey, Operator: corev1.NodeSelectorOpIn, Values: []string{host}},
+								}},
+							},
+						},
+					},
+				},
+			}
+		}
+		c := fake.NewClientBuilder().WithObjects(
+			localPV("pv-1", "local-nvme", "node-b"),
+			localPV("pv-2", "local-nvme", "node-a"),
+			localPV("pv-3", "local-nvme", "node-b"),
+			localPV("pv-4", "gp3", "node-c"),
+		).Build()
+
+		validator := template("subtree-validator")
+		Expect(SetScratch(ctx, c, validator, &v1alpha1.ScratchDef{
+			Local: &v1alpha1.ScratchVolume{StorageClass: "local-nvme", Size: resource.MustParse("100Gi")},
+		})).To(Succeed())
+		Expect(validator.Spec.Volumes).To(HaveLen(1))
+		claim := validator.Spec.Volumes[0].Ephemeral.VolumeClaimTemplate
+		Expect(*claim.Spec.StorageClassName).To(Equal("local-nvme"))
+		Expect(claim.Spec.Resources.Requests.Storage().String()).To(Equal("100Gi"))
+		Expect(claim.Labels).To(HaveKeyWithValue(v1alpha1.TeranodeLabel, "true"))
+		terms := validator.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchExpressions).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key:      HostnameTopologyKey,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"node-a", "node-b"},
+		}))
+	})
+})
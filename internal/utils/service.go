@@ -1,5 +1,15 @@
 package utils
 
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
 // GetPrometheusLabels returns a map of labels used for prometheus scraping
 func GetPrometheusLabels() map[string]string {
 	return map[string]string{
@@ -7,3 +17,81 @@ func GetPrometheusLabels() map[string]string {
 		"prometheus.io/scrape": "true",
 	}
 }
+
+// SetServiceOverrides replaces the service annotations and labels taken from the spec with the ones of the parent
+// cluster, then the ones of the service itself
+func SetServiceOverrides(c client.Client, svc *corev1.Service, cr v1alpha1.TeranodeService) {
+	var annotations, labels map[string]string
+	if clusterOwner := GetClusterOwner(c, context.Background(), cr.Metadata()); clusterOwner != nil {
+		annotations = mergeStringMaps(annotations, clusterOwner.Spec.ServiceAnnotations)
+		labels = mergeStringMaps(labels, clusterOwner.Spec.ServiceLabels)
+	}
+	if cr.DeploymentOverrides() != nil {
+		annotations = mergeStringMaps(annotations, cr.DeploymentOverrides().ServiceAnnotations)
+		labels = mergeStringMaps(labels, cr.DeploymentOverrides().ServiceLabels)
+	}
+	// propagation predates the deployment overrides with its own service annotations
+	if propagation, ok := cr.(*v1alpha1.Propagation); ok {
+		annotations = mergeStringMaps(annotations, propagation.Spec.ServiceAnnotations)
+	}
+	ReplaceAnnotations(svc, annotations)
+	ReplaceLabels(svc, labels)
+	SetServiceIPFamilies(c, svc, cr)
+}
+
+// GetServiceIPFamilies returns the IP families and policy of the service, falling back to the ones of the parent cluster
+func GetServiceIPFamilies(c client.Client, cr v1alpha1.TeranodeService) ([]corev1.IPFamily, *corev1.IPFamilyPolicy) {
+	if overrides := cr.DeploymentOverrides(); overrides != nil && (len(overrides.IPFamilies) > 0 || overrides.IPFamilyPolicy != nil) {
+		return overrides.IPFamilies, overrides.IPFamilyPolicy
+	}
+	if clusterOwner := GetClusterOwner(c, context.Background(), cr.Metadata()); clusterOwner != nil {
+		return clusterOwner.Spec.IPFamilies, clusterOwner.Spec.IPFamilyPolicy
+	}
+	return nil, nil
+}
+
+// SetServiceIPFamilies replaces the default IP families of the service when the service or the cluster configures them
+func SetServiceIPFamilies(c client.Client, svc *corev1.Service, cr v1alpha1.TeranodeService) {
+	families, policy := GetServiceIPFamilies(c, cr)
+	ApplyIPFamilies(svc, families, policy)
+}
+
+// ApplyIPFamilies sets the IP families and policy on the service, unless neither is set
+func ApplyIPFamilies(svc *corev1.Service, families []corev1.IPFamily, policy *corev1.IPFamilyPolicy) {
+	if len(families) == 0 && policy == nil {
+		return
+	}
+	// two families without a policy would be rejected by the API server
+	if policy == nil && len(families) > 1 {
+		policy = ptr.To(corev1.IPFamilyPolicyPreferDualStack)
+	}
+	svc.Spec.IPFamilies = families
+	svc.Spec.IPFamilyPolicy = policy
+}
+
+// HasIPFamily returns whether the IP families and policy serve the given family. A dual-stack
+// policy without explicit families serves both.
+func HasIPFamily(families []corev1.IPFamily, policy *corev1.IPFamilyPolicy, family corev1.IPFamily) bool {
+	if len(families) == 0 {
+		return policy != nil && *policy != corev1.IPFamilyPolicySingleStack
+	}
+	for _, f := range families {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeStringMaps(target, values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return target
+	}
+	if target == nil {
+		target = map[string]string{}
+	}
+	for k, v := range values {
+		target[k] = v
+	}
+	return target
+}
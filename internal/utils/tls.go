@@ -0,0 +1,81 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// Internal TLS certificates of the services
+const (
+	// InternalTLSChecksumAnnotation is set on the service resources by the cluster, and on their pods,
+	// so that the pods are restarted when their certificate is renewed
+	InternalTLSChecksumAnnotation = "teranode.bsvblockchain.org/internal-tls-checksum"
+	InternalTLSMountPath          = "/etc/teranode/internal-tls"
+	internalTLSVolumeName         = "internal-tls"
+)
+
+// Teranode settings enabling mutual TLS on gRPC
+const (
+	GRPCSecurityLevelSetting = "securityLevelGRPC"
+	GRPCCertFileSetting      = "server_certFile"
+	GRPCKeyFileSetting       = "server_keyFile"
+	GRPCCACertFileSetting    = "grpc_caCertFile"
+	// GRPCSecurityLevelMutualTLS requires client certificates signed by the CA
+	GRPCSecurityLevelMutualTLS = "3"
+)
+
+// InternalTLSSecretName is the secret holding the internal certificate of an app
+func InternalTLSSecretName(app string) string {
+	return "internal-tls-" + app
+}
+
+// SetInternalTLS mounts the internal certificate of the app and enables mutual TLS on gRPC
+func SetInternalTLS(template *corev1.PodTemplateSpec, cluster *v1alpha1.Cluster, cr v1alpha1.TeranodeService) {
+	if cluster == nil || cluster.Spec.InternalTLS == nil || len(template.Spec.Containers) == 0 {
+		return
+	}
+	app := template.Labels["app"]
+	if app == "" {
+		return
+	}
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: internalTLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: InternalTLSSecretName(app),
+			},
+		},
+	})
+	container := &template.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      internalTLSVolumeName,
+		MountPath: InternalTLSMountPath,
+		ReadOnly:  true,
+	})
+	for _, env := range []corev1.EnvVar{
+		{Name: GRPCSecurityLevelSetting, Value: GRPCSecurityLevelMutualTLS},
+		{Name: GRPCCertFileSetting, Value: InternalTLSMountPath + "/" + corev1.TLSCertKey},
+		{Name: GRPCKeyFileSetting, Value: InternalTLSMountPath + "/" + corev1.TLSPrivateKeyKey},
+		{Name: GRPCCACertFileSetting, Value: InternalTLSMountPath + "/" + corev1.ServiceAccountRootCAKey},
+	} {
+		if !hasEnv(container, env.Name) {
+			container.Env = append(container.Env, env)
+		}
+	}
+	if checksum := cr.Metadata().Annotations[InternalTLSChecksumAnnotation]; checksum != "" {
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[InternalTLSChecksumAnnotation] = checksum
+	}
+}
+
+func hasEnv(container *corev1.Container, name string) bool {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bsv-blockchain/teranode-operator/api/v1alpha1"
+)
+
+// StoreLayout is where a store is mounted, and the Teranode setting pointing to it
+type StoreLayout struct {
+	MountPath string
+	Setting   string
+}
+
+// StoreLayouts are the layouts of the stores that can be placed on a volume
+var StoreLayouts = map[v1alpha1.Store]StoreLayout{
+	v1alpha1.StoreBlocks:   {MountPath: "/data/blockstore", Setting: "blockstore"},
+	v1alpha1.StoreSubtrees: {MountPath: "/data/subtreestore", Setting: "subtreestore"},
+	v1alpha1.StoreTxs:      {MountPath: "/data/txstore", Setting: "txstore"},
+	v1alpha1.StoreTemp:     {MountPath: "/data/tmp", Setting: "temp_store"},
+}
+
+// serviceStores are the stores each app reads or writes
+var serviceStores = map[string][]v1alpha1.Store{
+	"asset":             {v1alpha1.StoreBlocks, v1alpha1.StoreSubtrees, v1alpha1.StoreTxs},
+	"block-assembly":    {v1alpha1.StoreSubtrees, v1alpha1.StoreTxs, v1alpha1.StoreTemp},
+	"block-persister":   {v1alpha1.StoreBlocks, v1alpha1.StoreSubtrees, v1alpha1.StoreTxs},
+	"block-validator":   {v1alpha1.StoreBlocks, v1alpha1.StoreSubtrees, v1alpha1.StoreTxs, v1alpha1.StoreTemp},
+	"legacy":            {v1alpha1.StoreBlocks, v1alpha1.StoreSubtrees, v1alpha1.StoreTxs, v1alpha1.StoreTemp},
+	"propagation":       {v1alpha1.StoreTxs},
+	"rpc":               {v1alpha1.StoreBlocks, v1alpha1.StoreSubtrees, v1alpha1.StoreTxs},
+	"subtree-validator": {v1alpha1.StoreSubtrees, v1alpha1.StoreTxs, v1alpha1.StoreTemp},
+	"utxo-persister":    {v1alpha1.StoreBlocks},
+	"validator":         {v1alpha1.StoreTxs},
+}
+
+// VolumePVCName is the PVC of a named volume of the cluster
+func VolumePVCName(name string) string {
+	return "cluster-storage-" + name
+}
+
+// StoreVolumes maps every store placed on a volume to the name of the volume. The CRD validation keeps a store
+// on a single volume.
+func StoreVolumes(cluster *v1alpha1.Cluster) map[v1alpha1.Store]string {
+	volumes := map[v1alpha1.Store]string{}
+	for _, volume := range cluster.Spec.Volumes {
+		for _, store := range volume.Stores {
+			volumes[store] = volume.Name
+		}
+	}
+	return volumes
+}
+
+// SetStoreVolumes mounts the volumes holding the stores the app uses, and points the store settings to them
+func SetStoreVolumes(template *corev1.PodTemplateSpec, cluster *v1alpha1.Cluster) {
+	if cluster == nil || len(cluster.Spec.Volumes) == 0 || len(template.Spec.Containers) == 0 {
+		return
+	}
+	volumes := StoreVolumes(cluster)
+	container := &template.Spec.Containers[0]
+	for _, store := range serviceStores[template.Labels["app"]] {
+		volume, ok := volumes[store]
+		if !ok {
+			continue
+		}
+		layout := StoreLayouts[store]
+		if !hasVolume(&template.Spec, VolumePVCName(volume)) {
+			template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+				Name: VolumePVCName(volume),
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: VolumePVCName(volume),
+					},
+				},
+			})
+		}
+		// every store is a directory of the volume, so that a volume can hold several stores
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      VolumePVCName(volume),
+			MountPath: layout.MountPath,
+			SubPath:   string(store),
+		})
+		if !hasEnv(container, layout.Setting) {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  layout.Setting,
+				Value: "file://" + layout.MountPath,
+			})
+		}
+	}
+}
+
+func hasVolume(podSpec *corev1.PodSpec, name string) bool {
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+	return false
+}